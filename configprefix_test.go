@@ -0,0 +1,52 @@
+package configreader_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mxmauro/configreader"
+	"github.com/mxmauro/configreader/loader"
+)
+
+// -----------------------------------------------------------------------------
+
+type ConfigPrefixTest struct {
+	Primary ConfigPrefixDatabaseTest  `configPrefix:"PRIMARY_"`
+	Replica *ConfigPrefixDatabaseTest `configPrefix:"REPLICA_"`
+}
+
+type ConfigPrefixDatabaseTest struct {
+	Host string `config:"DB_HOST"`
+	Port int    `config:"DB_PORT"`
+
+	Pool ConfigPrefixPoolTest `configPrefix:"POOL_"`
+}
+
+type ConfigPrefixPoolTest struct {
+	Size int `config:"SIZE"`
+}
+
+// -----------------------------------------------------------------------------
+
+func TestConfigPrefix(t *testing.T) {
+	// Load configuration
+	settings, err := configreader.New[ConfigPrefixTest]().
+		WithLoader(loader.NewMemory().WithData(map[string]interface{}{
+			"PRIMARY_DB_HOST":   "10.0.0.1",
+			"PRIMARY_DB_PORT":   5432,
+			"PRIMARY_POOL_SIZE": 10,
+			"REPLICA_DB_HOST":   "10.0.0.2",
+			"REPLICA_DB_PORT":   5433,
+			"REPLICA_POOL_SIZE": 20,
+		})).
+		Load(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	// Check if settings are the expected
+	if settings.Primary.Host != "10.0.0.1" || settings.Primary.Port != 5432 || settings.Primary.Pool.Size != 10 ||
+		settings.Replica.Host != "10.0.0.2" || settings.Replica.Port != 5433 || settings.Replica.Pool.Size != 20 {
+		t.Fatalf("settings mismatch")
+	}
+}
@@ -0,0 +1,154 @@
+package configreader
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/mxmauro/configreader/internal/helpers"
+)
+
+// -----------------------------------------------------------------------------
+
+// Decoder is implemented by types that can decode themselves from a configuration value.
+type Decoder interface {
+	DecodeConfig(value interface{}) error
+}
+
+// DecodeFunc converts a configuration value into a value of the type it was registered for.
+type DecodeFunc func(value interface{}) (interface{}, error)
+
+// -----------------------------------------------------------------------------
+
+var (
+	typeOfDecoder         = reflect.TypeOf((*Decoder)(nil)).Elem()
+	typeOfTextUnmarshaler = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// -----------------------------------------------------------------------------
+
+func (cr *ConfigReader[T]) decodeCustom(field reflect.Value, vToSet interface{}, fi *fieldInfo) (bool, error) {
+	// Keep the outermost field, so it is reset to nil, instead of pointing to a zero value, if there is no value to set
+	outerField := field
+	isPtr := field.Kind() == reflect.Pointer
+
+	// Keep the pointer to the innermost field, so decoded pointers can be stored as they are
+	var parentField reflect.Value
+
+	// Check for a registered decoder matching the field type or any of the pointed types
+	for {
+		if decoder, ok := cr.decoders[field.Type()]; ok {
+			if vToSet == nil {
+				return true, setNilValue(outerField, isPtr, fi)
+			}
+
+			value, err := decoder(vToSet)
+			if err != nil {
+				return true, newUnableToDecodeFieldError(fi.parentName, fi.name, err)
+			}
+
+			rValue := reflect.ValueOf(value)
+			if !rValue.IsValid() {
+				field.Set(reflect.Zero(field.Type()))
+			} else if rValue.Type().AssignableTo(field.Type()) {
+				field.Set(rValue)
+			} else if rValue.Type().ConvertibleTo(field.Type()) {
+				field.Set(rValue.Convert(field.Type()))
+			} else {
+				return true, fmt.Errorf("decoder returned an incompatible type for field \"%s%s\"", fi.parentName, fi.name)
+			}
+			return true, nil
+		}
+
+		if field.Kind() != reflect.Pointer {
+			break
+		}
+		if field.IsNil() {
+			field.Set(reflect.New(field.Type().Elem()))
+		}
+		parentField = field
+		field = field.Elem()
+	}
+
+	// Check for a built-in decoder
+	if decoder, ok := builtinDecoders[field.Type()]; ok {
+		valueStr, isNil, ok := helpers.ToString(vToSet)
+		if !ok {
+			return true, newUnableToConvertFieldError(fi.parentName, fi.name)
+		}
+		if isNil {
+			return true, setNilValue(outerField, isPtr, fi)
+		}
+
+		value, err := decoder(strings.TrimSpace(valueStr), fi)
+		if err != nil {
+			return true, newUnableToDecodeFieldError(fi.parentName, fi.name, err)
+		}
+
+		// Values like time.Location and regexp.Regexp must not be copied, so, if the decoder returned a pointer, store
+		// it as is and only copy the value if the field is not a pointer
+		rValue := reflect.ValueOf(value)
+		if rValue.Kind() == reflect.Pointer {
+			if parentField.IsValid() {
+				parentField.Set(rValue)
+			} else {
+				field.Set(rValue.Elem())
+			}
+		} else {
+			field.Set(rValue)
+		}
+		return true, nil
+	}
+
+	// Check if the type implements one of the supported decoding interfaces
+	if !field.CanAddr() {
+		return false, nil
+	}
+	ptrType := field.Addr().Type()
+	switch {
+	case ptrType.Implements(typeOfDecoder):
+		if vToSet == nil {
+			return true, setNilValue(outerField, isPtr, fi)
+		}
+
+		err := field.Addr().Interface().(Decoder).DecodeConfig(vToSet)
+		if err != nil {
+			return true, newUnableToDecodeFieldError(fi.parentName, fi.name, err)
+		}
+
+	case ptrType.Implements(typeOfTextUnmarshaler):
+		valueStr, isNil, ok := helpers.ToString(vToSet)
+		if !ok {
+			return true, newUnableToConvertFieldError(fi.parentName, fi.name)
+		}
+		if isNil {
+			return true, setNilValue(outerField, isPtr, fi)
+		}
+
+		err := field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(valueStr))
+		if err != nil {
+			return true, newUnableToDecodeFieldError(fi.parentName, fi.name, err)
+		}
+
+	default:
+		return false, nil
+	}
+
+	// Done
+	return true, nil
+}
+
+// -----------------------------------------------------------------------------
+
+func setNilValue(field reflect.Value, isPtr bool, fi *fieldInfo) error {
+	if !isPtr {
+		return newNoDefaultValueForFieldError(fi.parentName, fi.name)
+	}
+	field.Set(reflect.Zero(field.Type()))
+	return nil
+}
+
+func newUnableToDecodeFieldError(parentName string, structFieldName string, err error) error {
+	return fmt.Errorf("unable to decode value for field \"%s%s\" [err=%w]", parentName, structFieldName, err)
+}
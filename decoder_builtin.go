@@ -0,0 +1,91 @@
+package configreader
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// -----------------------------------------------------------------------------
+
+// builtinDecodeFunc returns either a value of the type it was registered for or a pointer to it
+type builtinDecodeFunc func(s string, fi *fieldInfo) (interface{}, error)
+
+// -----------------------------------------------------------------------------
+
+var builtinDecoders = map[reflect.Type]builtinDecodeFunc{
+	reflect.TypeOf(time.Time{}):     decodeTime,
+	reflect.TypeOf(time.Location{}): decodeLocation,
+	reflect.TypeOf(url.URL{}):       decodeURL,
+	reflect.TypeOf(net.IP{}):        decodeIP,
+	reflect.TypeOf(netip.Addr{}):    decodeNetIPAddr,
+	reflect.TypeOf(netip.Prefix{}):  decodeNetIPPrefix,
+	reflect.TypeOf(regexp.Regexp{}): decodeRegexp,
+}
+
+// -----------------------------------------------------------------------------
+
+func decodeTime(s string, fi *fieldInfo) (interface{}, error) {
+	layout := fi.layout
+	if len(layout) == 0 {
+		layout = time.RFC3339
+	}
+	return time.Parse(layout, s)
+}
+
+func decodeLocation(s string, _ *fieldInfo) (interface{}, error) {
+	loc, err := time.LoadLocation(s)
+	if err != nil {
+		return nil, err
+	}
+	return loc, nil
+}
+
+func decodeURL(s string, fi *fieldInfo) (interface{}, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate scheme
+	if len(u.Scheme) == 0 {
+		return nil, errors.New("missing url scheme")
+	}
+	if len(fi.schemes) > 0 && !slices.Contains(fi.schemes, strings.ToLower(u.Scheme)) {
+		return nil, fmt.Errorf("unsupported url scheme '%s'", u.Scheme)
+	}
+
+	// Done
+	return u, nil
+}
+
+func decodeIP(s string, _ *fieldInfo) (interface{}, error) {
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, errors.New("invalid ip address")
+	}
+	return ip, nil
+}
+
+func decodeNetIPAddr(s string, _ *fieldInfo) (interface{}, error) {
+	return netip.ParseAddr(s)
+}
+
+func decodeNetIPPrefix(s string, _ *fieldInfo) (interface{}, error) {
+	return netip.ParsePrefix(s)
+}
+
+func decodeRegexp(s string, _ *fieldInfo) (interface{}, error) {
+	re, err := regexp.Compile(s)
+	if err != nil {
+		return nil, err
+	}
+	return re, nil
+}
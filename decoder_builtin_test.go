@@ -0,0 +1,122 @@
+package configreader_test
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mxmauro/configreader"
+	"github.com/mxmauro/configreader/loader"
+	"github.com/mxmauro/configreader/model"
+)
+
+// -----------------------------------------------------------------------------
+
+type BuiltinDecoderTest struct {
+	StartAt  time.Time      `config:"TEST_START_AT"`
+	Date     time.Time      `config:"TEST_DATE" layout:"2006-01-02"`
+	Location *time.Location `config:"TEST_LOCATION"`
+	Endpoint *url.URL       `config:"TEST_ENDPOINT" schemes:"http,https"`
+	IP       net.IP         `config:"TEST_IP"`
+	Addr     netip.Addr     `config:"TEST_ADDR"`
+	Prefix   netip.Prefix   `config:"TEST_PREFIX"`
+	Pattern  *regexp.Regexp `config:"TEST_PATTERN"`
+}
+
+type BuiltinDecoderValuesTest struct {
+	Location time.Location `config:"TEST_LOCATION"`
+	Pattern  regexp.Regexp `config:"TEST_PATTERN"`
+}
+
+// -----------------------------------------------------------------------------
+
+func TestBuiltinDecoders(t *testing.T) {
+	// Load configuration
+	settings, err := configreader.New[BuiltinDecoderTest]().
+		WithLoader(loader.NewMemory().WithData(createBuiltinDecoderTestValues())).
+		Load(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	// Check if settings are the expected
+	if !settings.StartAt.Equal(time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)) ||
+		!settings.Date.Equal(time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)) ||
+		settings.Location.String() != "UTC" ||
+		settings.Endpoint.String() != "https://example.com/api" ||
+		!settings.IP.Equal(net.ParseIP("10.1.2.3")) ||
+		settings.Addr != netip.MustParseAddr("::1") ||
+		settings.Prefix != netip.MustParsePrefix("10.0.0.0/8") ||
+		!settings.Pattern.MatchString("abc-123") {
+		t.Fatalf("settings mismatch")
+	}
+}
+
+func TestBuiltinDecoderURLScheme(t *testing.T) {
+	values := createBuiltinDecoderTestValues()
+	values["TEST_ENDPOINT"] = "ftp://example.com/api"
+
+	// Load configuration
+	_, err := configreader.New[BuiltinDecoderTest]().
+		WithLoader(loader.NewMemory().WithData(values)).
+		Load(context.Background())
+	if err == nil {
+		t.Fatalf("unexpected success")
+	}
+	if !strings.Contains(err.Error(), "Endpoint") {
+		t.Fatalf("unexpected error [err=%v]", err)
+	}
+}
+
+func TestBuiltinDecoderLocalLocation(t *testing.T) {
+	values := createBuiltinDecoderTestValues()
+	values["TEST_LOCATION"] = "Local"
+
+	// Load configuration
+	settings, err := configreader.New[BuiltinDecoderTest]().
+		WithLoader(loader.NewMemory().WithData(values)).
+		Load(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	// The field must point to the local location itself and not to a copy of it
+	if settings.Location != time.Local {
+		t.Fatalf("location mismatch")
+	}
+}
+
+func TestBuiltinDecoderNonPointers(t *testing.T) {
+	// Load configuration
+	settings, err := configreader.New[BuiltinDecoderValuesTest]().
+		WithLoader(loader.NewMemory().WithData(createBuiltinDecoderTestValues())).
+		Load(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	// Check if settings are the expected
+	if settings.Location.String() != "UTC" || !settings.Pattern.MatchString("abc-123") {
+		t.Fatalf("settings mismatch")
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+func createBuiltinDecoderTestValues() model.Values {
+	return model.Values{
+		"TEST_START_AT": "2024-05-01T10:30:00Z",
+		"TEST_DATE":     "2024-05-02",
+		"TEST_LOCATION": "UTC",
+		"TEST_ENDPOINT": "https://example.com/api",
+		"TEST_IP":       "10.1.2.3",
+		"TEST_ADDR":     "::1",
+		"TEST_PREFIX":   "10.0.0.0/8",
+		"TEST_PATTERN":  `^[a-z]+-\d+$`,
+	}
+}
@@ -0,0 +1,133 @@
+package configreader_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mxmauro/configreader"
+	"github.com/mxmauro/configreader/loader"
+	"github.com/mxmauro/configreader/model"
+)
+
+// -----------------------------------------------------------------------------
+
+type DecoderTest struct {
+	Level    slog.Level        `config:"TEST_LEVEL"`
+	Addr     netip.Addr        `config:"TEST_ADDR"`
+	Mode     DecoderModeTest   `config:"TEST_MODE"`
+	Modes    []DecoderModeTest `config:"TEST_MODES"`
+	Endpoint *url.URL          `config:"TEST_ENDPOINT"`
+}
+
+type DecoderOptionalTest struct {
+	When     *time.Time       `config:"TEST_WHEN"`
+	Addr     *netip.Addr      `config:"TEST_ADDR"`
+	Mode     *DecoderModeTest `config:"TEST_MODE"`
+	Endpoint *url.URL         `config:"TEST_ENDPOINT"`
+}
+
+type DecoderModeTest int
+
+// -----------------------------------------------------------------------------
+
+const (
+	DecoderModeTestRead DecoderModeTest = iota + 1
+	DecoderModeTestWrite
+)
+
+// -----------------------------------------------------------------------------
+
+func (m *DecoderModeTest) DecodeConfig(value interface{}) error {
+	s, ok := value.(string)
+	if !ok {
+		return errors.New("not a string")
+	}
+	switch strings.ToLower(s) {
+	case "read":
+		*m = DecoderModeTestRead
+	case "write":
+		*m = DecoderModeTestWrite
+	default:
+		return fmt.Errorf("invalid mode '%s'", s)
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+func TestDecoders(t *testing.T) {
+	// Load configuration
+	settings, err := configreader.New[DecoderTest]().
+		WithLoader(loader.NewMemory().WithData(model.Values{
+			"TEST_LEVEL":    "warn",
+			"TEST_ADDR":     "192.168.1.1",
+			"TEST_MODE":     "write",
+			"TEST_MODES":    "read,write",
+			"TEST_ENDPOINT": "https://example.com/api",
+		})).
+		WithDecoder(reflect.TypeOf(&url.URL{}), func(value interface{}) (interface{}, error) {
+			return url.Parse(value.(string))
+		}).
+		Load(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	// Check if settings are the expected
+	if settings.Level != slog.LevelWarn ||
+		settings.Addr != netip.MustParseAddr("192.168.1.1") ||
+		settings.Mode != DecoderModeTestWrite ||
+		!reflect.DeepEqual(settings.Modes, []DecoderModeTest{DecoderModeTestRead, DecoderModeTestWrite}) ||
+		settings.Endpoint == nil || settings.Endpoint.Host != "example.com" {
+		t.Fatalf("settings mismatch")
+	}
+}
+
+func TestDecoderError(t *testing.T) {
+	// Load configuration
+	_, err := configreader.New[DecoderTest]().
+		WithLoader(loader.NewMemory().WithData(model.Values{
+			"TEST_LEVEL":    "warn",
+			"TEST_ADDR":     "192.168.1.1",
+			"TEST_MODE":     "execute",
+			"TEST_MODES":    "read",
+			"TEST_ENDPOINT": "https://example.com/api",
+		})).
+		Load(context.Background())
+	if err == nil {
+		t.Fatalf("unexpected success")
+	}
+	if !strings.Contains(err.Error(), "invalid mode 'execute'") {
+		t.Fatalf("unexpected error [err=%v]", err)
+	}
+}
+
+func TestDecoderUnsetPointers(t *testing.T) {
+	// Load configuration without values for the pointer fields
+	settings, err := configreader.New[DecoderOptionalTest]().
+		WithLoader(loader.NewMemory().WithData(model.Values{})).
+		WithDecoder(reflect.TypeOf(url.URL{}), func(value interface{}) (interface{}, error) {
+			u, err := url.Parse(value.(string))
+			if err != nil {
+				return nil, err
+			}
+			return *u, nil
+		}).
+		Load(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	// Fields without a value nor a default must stay nil
+	if settings.When != nil || settings.Addr != nil || settings.Mode != nil || settings.Endpoint != nil {
+		t.Fatalf("unset pointer fields are not nil")
+	}
+}
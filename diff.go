@@ -0,0 +1,119 @@
+package configreader
+
+import (
+	"reflect"
+	"strings"
+)
+
+// -----------------------------------------------------------------------------
+
+// Change describes a field whose value changed between two loads.
+type Change struct {
+	// Path is the field path, i.e. "Server.Port"
+	Path string
+	// OldValue is the previous value of the field
+	OldValue interface{}
+	// NewValue is the current value of the field
+	NewValue interface{}
+	// Redacted indicates the field contains a secret, so the old and new values were masked. If the field is an object,
+	// a list or a map, only the secret fields inside it are masked.
+	Redacted bool
+}
+
+// SettingsChangesCallback is a function to call when the re-loader detects a change in the configuration settings.
+// It receives the previous and the new settings along with the list of changed fields.
+type SettingsChangesCallback[T any] func(previous *T, settings *T, changes []Change)
+
+// -----------------------------------------------------------------------------
+
+func diffSettings[T any](previous *T, previousReport *Report, settings *T, report *Report) []Change {
+	previousEntries := collectDumpEntries(reflect.ValueOf(previous).Elem(), previousReport, true)
+	entries := collectDumpEntries(reflect.ValueOf(settings).Elem(), report, true)
+
+	previousEntriesMap := make(map[string]*dumpEntry, len(previousEntries))
+	for idx := range previousEntries {
+		previousEntriesMap[previousEntries[idx].path] = &previousEntries[idx]
+	}
+
+	changes := make([]Change, 0)
+	addChange := func(path string, oldEntry *dumpEntry, newEntry *dumpEntry) {
+		var oldValue, newValue interface{}
+		secret := false
+
+		if oldEntry != nil {
+			oldValue = oldEntry.value
+			secret = oldEntry.secret
+		}
+		if newEntry != nil {
+			newValue = newEntry.value
+			secret = secret || newEntry.secret
+		}
+		if reflect.DeepEqual(oldValue, newValue) {
+			return
+		}
+
+		change := Change{
+			Path:     path,
+			OldValue: oldValue,
+			NewValue: newValue,
+		}
+		if secret {
+			change.OldValue = redactedValue
+			change.NewValue = redactedValue
+			change.Redacted = true
+		} else {
+			// Mask the secret fields inside the value, if any. This is only done for the fields that changed.
+			if oldEntry != nil {
+				change.OldValue = dumpValue(oldEntry.field, true)
+				change.Redacted = !reflect.DeepEqual(change.OldValue, oldValue)
+			}
+			if newEntry != nil {
+				change.NewValue = dumpValue(newEntry.field, true)
+				change.Redacted = change.Redacted || !reflect.DeepEqual(change.NewValue, newValue)
+			}
+		}
+		changes = append(changes, change)
+	}
+
+	for idx := range entries {
+		e := &entries[idx]
+
+		if previousEntry, ok := previousEntriesMap[e.path]; ok {
+			addChange(e.path, previousEntry, e)
+			delete(previousEntriesMap, e.path)
+		} else {
+			addChange(e.path, nil, e)
+		}
+	}
+
+	// Add fields no longer present, like the ones inside a pointer to a struct that became nil
+	for idx := range previousEntries {
+		previousEntry := &previousEntries[idx]
+
+		if _, ok := previousEntriesMap[previousEntry.path]; ok {
+			addChange(previousEntry.path, previousEntry, nil)
+		}
+	}
+
+	// Done
+	return changes
+}
+
+// filterChanges returns the changes affecting any of the given paths or all of them if no path is specified. A path
+// matches the field itself and, if it is a struct, all the fields inside it.
+func filterChanges(changes []Change, paths []string) []Change {
+	if len(paths) == 0 {
+		return changes
+	}
+
+	filtered := make([]Change, 0)
+	for _, change := range changes {
+		for _, path := range paths {
+			if change.Path == path || strings.HasPrefix(change.Path, path+".") {
+				filtered = append(filtered, change)
+				break
+			}
+		}
+	}
+	return filtered
+}
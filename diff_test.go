@@ -0,0 +1,164 @@
+package configreader_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mxmauro/configreader"
+	"github.com/mxmauro/configreader/loader"
+	"github.com/mxmauro/configreader/model"
+)
+
+// -----------------------------------------------------------------------------
+
+type DiffTest struct {
+	Password string `config:"DIFF_TEST_PASSWORD" secret:"true"`
+	Server   DiffServerTest
+}
+
+type DiffServerTest struct {
+	Host string `config:"DIFF_TEST_SERVER_HOST"`
+	Port int    `config:"DIFF_TEST_SERVER_PORT"`
+}
+
+type DiffNestedSecretsTest struct {
+	Databases map[string]DiffDatabaseTest `config:"DIFF_TEST_DBS"`
+}
+
+type DiffDatabaseTest struct {
+	Host     string `config:"host"`
+	Password string `config:"password" secret:"true"`
+}
+
+// -----------------------------------------------------------------------------
+
+func TestMonitorChanges(t *testing.T) {
+	mtx := sync.Mutex{}
+	values := model.Values{
+		"DIFF_TEST_PASSWORD":    "secret1",
+		"DIFF_TEST_SERVER_HOST": "localhost",
+		"DIFF_TEST_SERVER_PORT": 80,
+	}
+	setValue := func(key string, value interface{}) {
+		mtx.Lock()
+		values[key] = value
+		mtx.Unlock()
+	}
+
+	allCh := make(chan []configreader.Change, 10)
+	serverCh := make(chan []configreader.Change, 10)
+
+	settingsMonitor := configreader.NewMonitor[DiffTest](100*time.Millisecond, nil).
+		WithChangesCallback(func(previous *DiffTest, settings *DiffTest, changes []configreader.Change) {
+			if previous == nil || settings == nil {
+				t.Errorf("settings not provided")
+			}
+			allCh <- changes
+		}).
+		WithChangesCallback(func(_ *DiffTest, _ *DiffTest, changes []configreader.Change) {
+			serverCh <- changes
+		}, "Server")
+	defer settingsMonitor.Destroy()
+
+	_, err := configreader.New[DiffTest]().
+		WithLoader(loader.NewCallback().WithCallback(func(_ context.Context) (model.Values, error) {
+			mtx.Lock()
+			defer mtx.Unlock()
+
+			ret := make(model.Values)
+			for k, v := range values {
+				ret[k] = v
+			}
+			return ret, nil
+		})).
+		WithMonitor(settingsMonitor).
+		Load(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	// Change a secret
+	setValue("DIFF_TEST_PASSWORD", "secret2")
+
+	changes := waitForChanges(t, allCh)
+	if len(changes) != 1 || changes[0].Path != "Password" || !changes[0].Redacted || changes[0].NewValue != "******" {
+		t.Fatalf("unexpected changes %+v", changes)
+	}
+
+	// Change the server port
+	setValue("DIFF_TEST_SERVER_PORT", 8080)
+
+	changes = waitForChanges(t, allCh)
+	if len(changes) != 1 || changes[0].Path != "Server.Port" || changes[0].OldValue != 80 || changes[0].NewValue != 8080 {
+		t.Fatalf("unexpected changes %+v", changes)
+	}
+
+	// The server subscriber must only be notified about the last change
+	changes = waitForChanges(t, serverCh)
+	if len(changes) != 1 || changes[0].Path != "Server.Port" {
+		t.Fatalf("unexpected changes %+v", changes)
+	}
+	select {
+	case changes = <-serverCh:
+		t.Fatalf("unexpected changes %+v", changes)
+	default:
+	}
+}
+
+func TestMonitorChangesNestedSecrets(t *testing.T) {
+	var password atomic.Value
+	password.Store("hunter1")
+
+	allCh := make(chan []configreader.Change, 10)
+
+	settingsMonitor := configreader.NewMonitor[DiffNestedSecretsTest](100*time.Millisecond, nil).
+		WithChangesCallback(func(_ *DiffNestedSecretsTest, _ *DiffNestedSecretsTest, changes []configreader.Change) {
+			allCh <- changes
+		})
+	defer settingsMonitor.Destroy()
+
+	_, err := configreader.New[DiffNestedSecretsTest]().
+		WithLoader(loader.NewCallback().WithCallback(func(_ context.Context) (model.Values, error) {
+			return model.Values{
+				"DIFF_TEST_DBS": map[string]interface{}{
+					"a": map[string]interface{}{
+						"host":     "h1",
+						"password": password.Load().(string),
+					},
+				},
+			}, nil
+		})).
+		WithMonitor(settingsMonitor).
+		Load(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	// Change a secret inside a map of structs
+	password.Store("hunter2")
+
+	changes := waitForChanges(t, allCh)
+	if len(changes) != 1 || changes[0].Path != "Databases" || !changes[0].Redacted {
+		t.Fatalf("unexpected changes %+v", changes)
+	}
+	if s := fmt.Sprintf("%v %v", changes[0].OldValue, changes[0].NewValue); strings.Contains(s, "hunter") || !strings.Contains(s, "h1") {
+		t.Fatalf("unexpected change values %v", s)
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+func waitForChanges(t *testing.T, ch chan []configreader.Change) []configreader.Change {
+	select {
+	case changes := <-ch:
+		return changes
+	case <-time.After(5 * time.Second):
+		t.Fatalf("changes not received")
+	}
+	return nil
+}
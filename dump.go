@@ -0,0 +1,256 @@
+package configreader
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mxmauro/configreader/internal/helpers"
+	"github.com/mxmauro/configreader/loader"
+)
+
+// -----------------------------------------------------------------------------
+
+// DumpFormat specifies the output format used by Dump.
+type DumpFormat int
+
+const (
+	// DumpFormatTable renders a human-readable table with the field path, key, value and source
+	DumpFormatTable DumpFormat = iota
+	// DumpFormatJSON renders a JSON object keyed by field path
+	DumpFormatJSON
+	// DumpFormatDotEnv renders a dotenv file keyed by configuration key
+	DumpFormatDotEnv
+)
+
+const (
+	redactedValue = "******"
+)
+
+// -----------------------------------------------------------------------------
+
+type dumpEntry struct {
+	path   string
+	key    string
+	value  interface{}
+	source string
+	secret bool
+	field  reflect.Value
+}
+
+// -----------------------------------------------------------------------------
+
+// Dump writes the provided settings using the specified format. Fields tagged with `secret:"true"` and, if a report
+// is provided, values loaded from Vault are masked.
+func Dump[T any](w io.Writer, settings *T, report *Report, format DumpFormat) error {
+	if settings == nil {
+		return errors.New("settings not set")
+	}
+
+	// Collect values
+	entries := collectDumpEntries(reflect.ValueOf(settings).Elem(), report, false)
+
+	// Render
+	switch format {
+	case DumpFormatTable:
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "FIELD\tKEY\tVALUE\tSOURCE")
+		for _, e := range entries {
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", e.path, e.key, dumpValueToString(e.value), e.source)
+		}
+		return tw.Flush()
+
+	case DumpFormatJSON:
+		obj := make(map[string]interface{}, len(entries))
+		for _, e := range entries {
+			obj[e.path] = e.value
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(obj)
+
+	case DumpFormatDotEnv:
+		for _, e := range entries {
+			// Prefixed maps are written as individual keys
+			if strings.HasSuffix(e.key, "*") {
+				if m, ok := e.value.(map[string]interface{}); ok {
+					err := writeDotEnvMap(w, e.key[:len(e.key)-1], m)
+					if err != nil {
+						return err
+					}
+					continue
+				}
+			}
+
+			_, err := fmt.Fprintf(w, "%s=%s\n", e.key, quoteDotEnvValue(dumpValueToString(e.value)))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// Unknown format
+	return errors.New("unsupported dump format")
+}
+
+// -----------------------------------------------------------------------------
+
+// collectDumpEntries returns the values of the configuration fields. Unless raw values are requested, the ones of
+// secret fields are masked.
+func collectDumpEntries(v reflect.Value, report *Report, raw bool) []dumpEntry {
+	entries := make([]dumpEntry, 0)
+	_ = walkConfigFields(v, "", "", false, func(field reflect.Value, structField reflect.StructField, configTag string, parentName string, keyPrefix string) error {
+		if !structField.IsExported() {
+			return nil
+		}
+
+		e := dumpEntry{
+			path:  parentName + structField.Name,
+			key:   keyPrefix + configTag,
+			field: field,
+		}
+		if report != nil {
+			if reportField, ok := report.Fields[e.path]; ok {
+				e.source = reportField.Source
+			}
+		}
+		e.secret = isSecretField(structField.Tag, e.path, report)
+		if e.secret && !raw {
+			e.value = redactedValue
+		} else {
+			e.value = dumpValue(field, !raw)
+		}
+		entries = append(entries, e)
+		return nil
+	})
+	return entries
+}
+
+func isSecretField(tags reflect.StructTag, path string, report *Report) bool {
+	if secret, ok := helpers.Str2Bool(tags.Get("secret")); ok && secret {
+		return true
+	}
+	if report != nil {
+		if reportField, ok := report.Fields[path]; ok {
+			if _, isVault := reportField.Loader.(*loader.Vault); isVault {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// dumpValue converts the value into a representation suitable for encoding. Structs, including the ones inside maps,
+// slices and arrays, are converted into objects and, if requested, their fields tagged as secret are masked.
+func dumpValue(v reflect.Value, mask bool) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+
+	// Dereference pointers and interfaces
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	// Use the textual representation if available
+	if v.Type() == typeOfTimeDuration {
+		return time.Duration(v.Int()).String()
+	}
+	if s, ok := helpers.TextOf(v, true); ok {
+		return s
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		obj := make(map[string]interface{})
+		for idx := 0; idx < v.NumField(); idx++ {
+			structField := v.Type().Field(idx)
+			if !structField.IsExported() {
+				continue
+			}
+
+			if secret, ok := helpers.Str2Bool(structField.Tag.Get("secret")); mask && ok && secret {
+				obj[structField.Name] = redactedValue
+			} else {
+				obj[structField.Name] = dumpValue(v.Field(idx), mask)
+			}
+		}
+		return obj
+
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Bytes()
+		}
+		fallthrough
+	case reflect.Array:
+		arr := make([]interface{}, v.Len())
+		for idx := 0; idx < v.Len(); idx++ {
+			arr[idx] = dumpValue(v.Index(idx), mask)
+		}
+		return arr
+
+	case reflect.Map:
+		obj := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			obj[fmt.Sprint(iter.Key().Interface())] = dumpValue(iter.Value(), mask)
+		}
+		return obj
+
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return nil
+	}
+	return v.Interface()
+}
+
+func dumpValueToString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(encoded)
+}
+
+func writeDotEnvMap(w io.Writer, prefix string, m map[string]interface{}) error {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		_, err := fmt.Fprintf(w, "%s%s=%s\n", prefix, k, quoteDotEnvValue(dumpValueToString(m[k])))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func quoteDotEnvValue(s string) string {
+	if len(s) > 0 && !strings.ContainsAny(s, " \t\r\n'\"#\\") {
+		return s
+	}
+	// Prefer single quotes because no escaping is needed
+	if !strings.ContainsAny(s, "\r\n'") {
+		return "'" + s + "'"
+	}
+	return strconv.Quote(s)
+}
@@ -0,0 +1,179 @@
+package configreader_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mxmauro/configreader"
+	"github.com/mxmauro/configreader/loader"
+	"github.com/mxmauro/configreader/model"
+)
+
+// -----------------------------------------------------------------------------
+
+type DumpTest struct {
+	Name     string            `config:"DUMP_TEST_NAME"`
+	Password string            `config:"DUMP_TEST_PASSWORD" secret:"true"`
+	Timeout  time.Duration     `config:"DUMP_TEST_TIMEOUT"`
+	Tags     []string          `config:"DUMP_TEST_TAGS"`
+	Features map[string]bool   `config:"DUMP_TEST_FEATURE_*"`
+	Server   DumpServerTest    `configPrefix:"DUMP_TEST_SERVER_"`
+	Labels   map[string]string `config:"DUMP_TEST_LABELS"`
+}
+
+type DumpServerTest struct {
+	Host string `config:"HOST"`
+	Port int    `config:"PORT"`
+}
+
+type DumpNestedSecretsTest struct {
+	Database  DumpDatabaseTest            `config:"DUMP_TEST_DB"`
+	Databases map[string]DumpDatabaseTest `config:"DUMP_TEST_DBS"`
+	Replicas  []DumpDatabaseTest          `config:"DUMP_TEST_REPLICAS"`
+}
+
+type DumpDatabaseTest struct {
+	Host     string `config:"host"`
+	Password string `config:"password" secret:"true"`
+}
+
+// -----------------------------------------------------------------------------
+
+func TestDump(t *testing.T) {
+	settings, report, err := configreader.New[DumpTest]().
+		WithLoader(loader.NewMemory().WithData(model.Values{
+			"DUMP_TEST_NAME":           "my app",
+			"DUMP_TEST_PASSWORD":       "hunter2",
+			"DUMP_TEST_TIMEOUT":        "5s",
+			"DUMP_TEST_TAGS":           []interface{}{"a", "b"},
+			"DUMP_TEST_FEATURE_SEARCH": true,
+			"DUMP_TEST_FEATURE_EXPORT": false,
+			"DUMP_TEST_SERVER_HOST":    "localhost",
+			"DUMP_TEST_SERVER_PORT":    8080,
+			"DUMP_TEST_LABELS":         map[string]interface{}{"env": "dev"},
+		})).
+		LoadWithReport(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	// Table
+	buf := bytes.Buffer{}
+	err = configreader.Dump(&buf, settings, report, configreader.DumpFormatTable)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if strings.Contains(buf.String(), "hunter2") {
+		t.Fatalf("secret value found in table output")
+	}
+	if !strings.Contains(buf.String(), "Server.Port") || !strings.Contains(buf.String(), "Memory") {
+		t.Fatalf("unexpected table output\n%v", buf.String())
+	}
+
+	// JSON
+	buf.Reset()
+	err = configreader.Dump(&buf, settings, report, configreader.DumpFormatJSON)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	obj := make(map[string]interface{})
+	err = json.Unmarshal(buf.Bytes(), &obj)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if obj["Password"] != "******" || obj["Timeout"] != "5s" || obj["Server.Port"] != float64(8080) {
+		t.Fatalf("unexpected json output\n%v", buf.String())
+	}
+
+	// Dotenv
+	buf.Reset()
+	err = configreader.Dump(&buf, settings, report, configreader.DumpFormatDotEnv)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	for _, line := range []string{
+		`DUMP_TEST_NAME='my app'`,
+		`DUMP_TEST_PASSWORD=******`,
+		`DUMP_TEST_TAGS='["a","b"]'`,
+		`DUMP_TEST_FEATURE_EXPORT=false`,
+		`DUMP_TEST_FEATURE_SEARCH=true`,
+		`DUMP_TEST_SERVER_HOST=localhost`,
+		`DUMP_TEST_LABELS='{"env":"dev"}'`,
+	} {
+		if !strings.Contains(buf.String(), line+"\n") {
+			t.Fatalf("line %v not found in dotenv output\n%v", line, buf.String())
+		}
+	}
+}
+
+func TestDumpVaultSourcedValues(t *testing.T) {
+	settings := &DumpTest{
+		Name: "my app",
+	}
+	report := &configreader.Report{
+		Fields: map[string]configreader.ReportField{
+			"Name": {
+				Source: "Vault",
+				Loader: loader.NewVault(),
+			},
+		},
+	}
+
+	buf := bytes.Buffer{}
+	err := configreader.Dump(&buf, settings, report, configreader.DumpFormatDotEnv)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if !strings.Contains(buf.String(), "DUMP_TEST_NAME=******\n") {
+		t.Fatalf("vault sourced value not masked\n%v", buf.String())
+	}
+}
+
+func TestDumpNestedSecrets(t *testing.T) {
+	settings, err := configreader.New[DumpNestedSecretsTest]().
+		WithLoader(loader.NewMemory().WithData(model.Values{
+			"DUMP_TEST_DB": map[string]interface{}{
+				"host":     "h1",
+				"password": "hunter1",
+			},
+			"DUMP_TEST_DBS": map[string]interface{}{
+				"a": map[string]interface{}{
+					"host":     "h2",
+					"password": "hunter2",
+				},
+			},
+			"DUMP_TEST_REPLICAS": []interface{}{
+				map[string]interface{}{
+					"host":     "h3",
+					"password": "hunter3",
+				},
+			},
+		})).
+		Load(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	for _, format := range []configreader.DumpFormat{
+		configreader.DumpFormatTable, configreader.DumpFormatJSON, configreader.DumpFormatDotEnv,
+	} {
+		buf := bytes.Buffer{}
+		err = configreader.Dump(&buf, settings, nil, format)
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+		if strings.Contains(buf.String(), "hunter") {
+			t.Fatalf("secret value found in output\n%v", buf.String())
+		}
+		// Only the secret fields must be masked
+		for _, host := range []string{"h1", "h2", "h3"} {
+			if !strings.Contains(buf.String(), host) {
+				t.Fatalf("non-secret value %v not found in output\n%v", host, buf.String())
+			}
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package configreader_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mxmauro/configreader"
+	"github.com/mxmauro/configreader/internal/testhelpers"
+	"github.com/mxmauro/configreader/loader"
+	"github.com/mxmauro/configreader/model"
+)
+
+// -----------------------------------------------------------------------------
+
+type EnvVarsTest struct {
+	Home     string          `config:"HOME"`
+	Port     int             `config:"PORT"`
+	Url      string          `config:"URL"`
+	Features map[string]bool `config:"FEATURE_*"`
+}
+
+// -----------------------------------------------------------------------------
+
+func TestEnvPrefix(t *testing.T) {
+	defer testhelpers.ScopedEnvVars(map[string]string{
+		"HOME":                     "/home/somebody",
+		"PORT":                     "1000",
+		"ENVVARS_TEST_PORT":        "2000",
+		"ENVVARS_TEST_FEATURE_NEW": "true",
+		"ENVVARS_HOST":             "example.com",
+	})()
+
+	settings, err := configreader.New[EnvVarsTest]().
+		WithLoader(envVarsTestLoader()).
+		WithEnvPrefix("ENVVARS_TEST_").
+		Load(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if settings.Home != "/app" || settings.Port != 2000 || !settings.Features["NEW"] {
+		t.Fatalf("unexpected settings [home=%v, port=%v, features=%v]", settings.Home, settings.Port, settings.Features)
+	}
+
+	// Variables without the prefix must still be available for expansion
+	if settings.Url != "http://example.com:2000" {
+		t.Fatalf("unexpected url [url=%v]", settings.Url)
+	}
+}
+
+func TestDisableAllEnvVarOverride(t *testing.T) {
+	defer testhelpers.ScopedEnvVars(map[string]string{
+		"HOME":         "/home/somebody",
+		"PORT":         "1000",
+		"ENVVARS_HOST": "example.com",
+	})()
+
+	settings, err := configreader.New[EnvVarsTest]().
+		WithLoader(envVarsTestLoader()).
+		WithDisableAllEnvVarOverride().
+		Load(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if settings.Home != "/app" || settings.Port != 80 || settings.Url != "http://example.com:80" {
+		t.Fatalf("unexpected settings [home=%v, port=%v, url=%v]", settings.Home, settings.Port, settings.Url)
+	}
+}
+
+func TestKnownEnvVarsOnly(t *testing.T) {
+	defer testhelpers.ScopedEnvVars(map[string]string{
+		"PORT":          "1000",
+		"FEATURE_OTHER": "true",
+		"ENVVARS_HOST":  "example.com",
+	})()
+
+	settings, err := configreader.New[EnvVarsTest]().
+		WithLoader(envVarsTestLoader()).
+		WithKnownEnvVarsOnly().
+		WithStrict().
+		Load(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if settings.Port != 1000 || !settings.Features["OTHER"] || settings.Url != "http://example.com:1000" {
+		t.Fatalf("unexpected settings [port=%v, features=%v, url=%v]", settings.Port, settings.Features, settings.Url)
+	}
+}
+
+func TestDisableEnvVarOverride(t *testing.T) {
+	defer testhelpers.ScopedEnvVars(map[string]string{
+		"PORT":         "1000",
+		"ENVVARS_HOST": "example.com",
+	})()
+
+	settings, err := configreader.New[EnvVarsTest]().
+		WithLoader(envVarsTestLoader()).
+		WithDisableEnvVarOverride("PORT").
+		Load(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if settings.Port != 80 || settings.Url != "http://example.com:80" {
+		t.Fatalf("unexpected settings [port=%v, url=%v]", settings.Port, settings.Url)
+	}
+
+	// Excluded variables must not be available for expansion either
+	_, err = configreader.New[EnvVarsTest]().
+		WithLoader(envVarsTestLoader()).
+		WithDisableEnvVarOverride("ENVVARS_HOST").
+		Load(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "ENVVARS_HOST") {
+		t.Fatalf("unexpected result [err=%v]", err)
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+func envVarsTestLoader() model.Loader {
+	return loader.NewMemory().WithData(model.Values{
+		"HOME":            "/app",
+		"PORT":            80,
+		"URL":             "http://${ENVVARS_HOST}:${PORT}",
+		"FEATURE_DEFAULT": false,
+	})
+}
@@ -3,6 +3,8 @@ package configreader
 import (
 	"fmt"
 	"strings"
+
+	"github.com/mxmauro/configreader/model"
 )
 
 // -----------------------------------------------------------------------------
@@ -18,6 +20,38 @@ type ValidationErrorFailure struct {
 	Tag   string
 }
 
+// LoadError is returned when one or more loaders fail.
+type LoadError struct {
+	Failures []LoadErrorFailure
+}
+
+// LoadErrorFailure represents the error returned by a specific loader.
+type LoadErrorFailure struct {
+	// Loader is the loader that failed
+	Loader model.Loader
+	// LoaderIndex is the index of the loader in the order they were added
+	LoaderIndex int
+	// Err is the error returned by the loader
+	Err error
+}
+
+// ReloadRejectedError is passed to the monitor callback when a reload guard rejects the new settings.
+type ReloadRejectedError struct {
+	Err error
+}
+
+// UnknownKeysError is returned in strict mode when loaders provide keys not used by any field.
+type UnknownKeysError struct {
+	Keys []UnknownKey
+}
+
+// UnknownKey represents a key not used by any field.
+type UnknownKey struct {
+	Key        string
+	Source     string
+	Suggestion string
+}
+
 // -----------------------------------------------------------------------------
 
 func (e *ValidationError) Error() string {
@@ -36,3 +70,57 @@ func (*ValidationError) Unwrap() error {
 func (e *ValidationErrorFailure) Error() string {
 	return fmt.Sprintf("unable to validate '%s' on field '%s'", e.Tag, e.Field)
 }
+
+func (e *LoadError) Error() string {
+	sb := strings.Builder{}
+	_, _ = sb.WriteString("unable to load settings")
+	for idx := range e.Failures {
+		_, _ = sb.WriteString(fmt.Sprintf(" [%d:%s]", idx+1, e.Failures[idx].Error()))
+	}
+	return sb.String()
+}
+
+func (e *LoadError) Unwrap() []error {
+	errs := make([]error, len(e.Failures))
+	for idx := range e.Failures {
+		errs[idx] = e.Failures[idx].Err
+	}
+	return errs
+}
+
+func (e *LoadErrorFailure) Error() string {
+	return fmt.Sprintf("%s loader #%d failed [err=%v]", loaderName(e.Loader), e.LoaderIndex+1, e.Err)
+}
+
+func (e *LoadErrorFailure) Unwrap() error {
+	return e.Err
+}
+
+func (e *ReloadRejectedError) Error() string {
+	return fmt.Sprintf("reloaded settings rejected [err=%v]", e.Err)
+}
+
+func (e *ReloadRejectedError) Unwrap() error {
+	return e.Err
+}
+
+func (e *UnknownKeysError) Error() string {
+	sb := strings.Builder{}
+	_, _ = sb.WriteString("unknown keys found")
+	for idx := range e.Keys {
+		_, _ = sb.WriteString(fmt.Sprintf(" [%d:%s]", idx+1, e.Keys[idx].Error()))
+	}
+	return sb.String()
+}
+
+func (*UnknownKeysError) Unwrap() error {
+	return nil
+}
+
+func (e *UnknownKey) Error() string {
+	s := fmt.Sprintf("unknown key '%s' provided by %s", e.Key, e.Source)
+	if len(e.Suggestion) > 0 {
+		s += fmt.Sprintf(", did you mean '%s'?", e.Suggestion)
+	}
+	return s
+}
@@ -47,7 +47,7 @@ func TestInvalidExtendedValidator(t *testing.T) {
 func testExtendedValidatorCommon(data model.Values) error {
 	_, err := configreader.New[ExtendedValidatorTest]().
 		WithLoader(loader.NewMemory().WithData(data)).
-		WithExtendedValidator(func(settings *ExtendedValidatorTest) error {
+		WithExtendedValidator(func(_ context.Context, settings *ExtendedValidatorTest) error {
 			if settings.Value < 1 {
 				return ExtendedValidatorValueError
 			}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/mxmauro/configreader/internal/helpers"
@@ -15,6 +16,21 @@ import (
 
 // -----------------------------------------------------------------------------
 
+type fieldInfo struct {
+	parentName   string
+	name         string
+	keyPrefix    string
+	values       model.Values
+	state        *fillState
+	typeOverride string
+	separator    string
+	layout       string
+	schemes      []string
+	isElement    bool
+}
+
+// -----------------------------------------------------------------------------
+
 var (
 	typeOfTimeDuration = reflect.TypeOf(time.Duration(0))
 )
@@ -34,12 +50,13 @@ func (cr *ConfigReader[T]) checkT() error {
 	return nil
 }
 
-func (cr *ConfigReader[T]) fillFields(settings *T, values model.Values) error {
+func (cr *ConfigReader[T]) fillFields(settings *T, values model.Values, state *fillState) error {
 	rSettings := reflect.ValueOf(settings).Elem()
-	return cr.fillFieldsRecursive(rSettings, "", values)
+	return cr.fillFieldsRecursive(rSettings, "", "", values, state)
 }
 
-func (cr *ConfigReader[T]) fillFieldsRecursive(v reflect.Value, parentName string, values model.Values) error {
+// NOTE: The state is nil when the struct is populated from a nested object instead of the top-level values
+func (cr *ConfigReader[T]) fillFieldsRecursive(v reflect.Value, parentName string, keyPrefix string, values model.Values, state *fillState) error {
 	// Ignore non-valid items
 	if !v.IsValid() {
 		return nil
@@ -52,330 +69,483 @@ func (cr *ConfigReader[T]) fillFieldsRecursive(v reflect.Value, parentName strin
 
 	// Allocate pointers if needed
 	v = ptrAlloc(v)
-	vType := v.Type()
 
 	// Populate each field
-	for fIdx := 0; fIdx < v.NumField(); fIdx++ {
-		field := v.Field(fIdx)
-		structField := vType.Field(fIdx)
+	return walkConfigFields(v, parentName, keyPrefix, true, func(field reflect.Value, structField reflect.StructField, configTag string, parentName string, keyPrefix string) error {
+		return cr.fillField(field, structField, configTag, parentName, keyPrefix, values, state)
+	})
+}
 
-		// Analyze field tags
-		tags := structField.Tag
+func (cr *ConfigReader[T]) fillField(field reflect.Value, structField reflect.StructField, configTag string, parentName string, keyPrefix string, values model.Values, state *fillState) error {
+	// Analyze field tags
+	tags := structField.Tag
 
-		// Get our tag
-		configTag := tags.Get("config")
-		if len(configTag) == 0 {
-			// This field has no configuration but handle it if a struct or a pointer to one
-			effField := ptrAlloc(field)
-			if effField.Kind() == reflect.Struct {
-				// Create struct object
-				effField.Set(reflect.Zero(effField.Type()))
+	// Signal error if field cannot be written
+	if !field.CanSet() {
+		return fmt.Errorf("field \"%s%s\" is not settable", parentName, structField.Name)
+	}
 
-				// Go deeper
-				err := cr.fillFieldsRecursive(effField, parentName+structField.Name+".", values)
-				if err != nil {
-					return err
-				}
-			}
+	// Get default value to use if no value is present
+	defaultValue, defaultValuePresent := tags.Lookup("default")
 
-			// Jump to next field
-			continue
-		}
-
-		// Signal error if field cannot be written
-		if !field.CanSet() {
-			return fmt.Errorf("field \"%s%s\" is not settable", parentName, structField.Name)
+	// Check if this field should be treated as JSON
+	isJson, isJsonPresent := helpers.Str2Bool(tags.Get("isjson"))
+	if !isJsonPresent {
+		isJson, isJsonPresent = helpers.Str2Bool(tags.Get("is_json"))
+		if !isJsonPresent {
+			isJson, isJsonPresent = helpers.Str2Bool(tags.Get("is-json"))
+			if !isJsonPresent {
+				isJson = false
+			}
 		}
+	}
 
-		// Get default value to use if no value is present
-		defaultValue, defaultValuePresent := tags.Lookup("default")
-
-		// Get type override
-		typeOverride := tags.Get("type")
+	// Get value to store. A tag ending with an asterisk collects all the keys sharing the same prefix
+	var vToSet interface{}
+	var vToSetIsPresent bool
+	if strings.HasSuffix(configTag, "*") {
+		var prefixedValues map[string]interface{}
 
-		// Check if this field should be treated as JSON
-		isJson, isJsonPresent := helpers.Str2Bool(tags.Get("isjson"))
-		if !isJsonPresent {
-			isJson, isJsonPresent = helpers.Str2Bool(tags.Get("is_json"))
-			if !isJsonPresent {
-				isJson, isJsonPresent = helpers.Str2Bool(tags.Get("is-json"))
-				if !isJsonPresent {
-					isJson = false
-				}
+		prefix := keyPrefix + configTag[:len(configTag)-1]
+		if state != nil {
+			state.recordKnownPrefix(prefix)
+		}
+		prefixedValues, vToSetIsPresent = helpers.CollectPrefixedValues(values, prefix)
+		if vToSetIsPresent {
+			vToSet = prefixedValues
+			if state != nil {
+				state.recordPrefixedValues(parentName+structField.Name, prefix, prefixedValues)
+			}
+		}
+	} else {
+		if state != nil {
+			state.recordKnownKey(keyPrefix + configTag)
+		}
+		vToSet, vToSetIsPresent = helpers.LookupValue(values, keyPrefix+configTag)
+		if vToSetIsPresent && state != nil {
+			state.recordValue(parentName+structField.Name, keyPrefix+configTag)
+		}
+	}
+	if !vToSetIsPresent {
+		if defaultValuePresent {
+			vToSet = defaultValue
+			if state != nil {
+				state.recordDefault(parentName+structField.Name, keyPrefix+configTag, defaultValue)
 			}
+		} else {
+			vToSet = nil
 		}
+	}
 
-		// Get value to store
-		vToSet, vToSetIsPresent := values[configTag]
-		if !vToSetIsPresent {
-			if defaultValuePresent {
-				vToSet = defaultValue
-			} else {
-				vToSet = nil
+	// Treat as JSON?
+	if !isJson {
+		fi := fieldInfo{
+			parentName:   parentName,
+			name:         structField.Name,
+			keyPrefix:    keyPrefix,
+			values:       values,
+			state:        state,
+			typeOverride: tags.Get("type"),
+		}
+		fi.separator, _ = lookupTag(tags, "sep", "separator")
+		fi.layout = tags.Get("layout")
+		if schemes, ok := lookupTag(tags, "schemes", "scheme"); ok {
+			for _, scheme := range strings.Split(schemes, ",") {
+				fi.schemes = append(fi.schemes, strings.ToLower(strings.TrimSpace(scheme)))
 			}
 		}
 
+		err := cr.setValue(field, vToSet, &fi)
+		if err != nil {
+			return err
+		}
+
+	} else {
 		// Get effective field
 		effField := ptrAlloc(field)
 		effFieldIsPtr := field.Kind() == reflect.Pointer
 
-		// Signal error if field cannot be written
-		if !effField.CanSet() {
-			return fmt.Errorf("field \"%s%s\" is not settable", parentName, structField.Name)
+		valueStr, isNil, ok := helpers.ToString(vToSet)
+		if !ok {
+			return newUnableToConvertFieldErrorJSON(parentName, structField.Name)
 		}
+		if isNil {
+			if !effFieldIsPtr {
+				return newUnableToConvertFieldErrorJSON(parentName, structField.Name)
+			}
+			effField.Set(reflect.Zero(effField.Type()))
+		} else {
+			iface := effField.Addr().Interface()
 
-		// Treat as JSON?
-		if !isJson {
-			// Special cases
-			if typeOverride == "memory" {
-				// Special case for memory type
-				switch effField.Kind() {
-				case reflect.Int, reflect.Int32, reflect.Int64:
-					valueStr, isNil, ok := helpers.ToString(vToSet)
-					if !ok {
-						return newUnableToConvertFieldError(parentName, structField.Name)
-					}
-					if isNil {
-						if !effFieldIsPtr {
-							return newNoDefaultValueForFieldError(parentName, structField.Name)
-						}
-						effField.Set(reflect.Zero(effField.Type()))
-					} else {
-						m, err := parsers.ParseMemorySize(valueStr)
-						if err != nil {
-							return newUnableToConvertFieldError(parentName, structField.Name)
-						}
-						if helpers.OverflowCheckUint64(effField.Type(), m) {
-							return newOverflowFieldError(parentName, structField.Name)
-						}
-						effField.SetInt(int64(m))
-					}
+			// Unmarshal
+			err := json.Unmarshal([]byte(valueStr), iface)
+			if err != nil {
+				return err
+			}
+		}
 
-				case reflect.Uint, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
-					valueStr, isNil, ok := helpers.ToString(vToSet)
-					if !ok {
-						return newUnableToConvertFieldError(parentName, structField.Name)
-					}
-					if isNil {
-						if !effFieldIsPtr {
-							return newNoDefaultValueForFieldError(parentName, structField.Name)
-						}
-						effField.Set(reflect.Zero(effField.Type()))
-					} else {
-						m, err := parsers.ParseMemorySize(valueStr)
-						if err != nil {
-							return newUnableToConvertFieldError(parentName, structField.Name)
-						}
-						if helpers.OverflowCheckUint64(effField.Type(), m) {
-							return newOverflowFieldError(parentName, structField.Name)
-						}
-						effField.SetUint(m)
-					}
+	}
 
-				default:
-					return newUnableToConvertFieldError(parentName, structField.Name)
-				}
+	// Done
+	return nil
+}
 
-			} else if effField.Type() == typeOfTimeDuration {
+// -----------------------------------------------------------------------------
 
-				//Special case for time duration fields
-				switch effField.Kind() {
-				case reflect.Int, reflect.Int32, reflect.Int64:
-					valueStr, isNil, ok := helpers.ToString(vToSet)
-					if !ok {
-						return newUnableToConvertFieldError(parentName, structField.Name)
-					}
-					if isNil {
-						if !effFieldIsPtr {
-							return newNoDefaultValueForFieldError(parentName, structField.Name)
-						}
-						effField.Set(reflect.Zero(effField.Type()))
-					} else {
-						d, err := parsers.ParseDuration(valueStr)
-						if err != nil {
-							return newUnableToConvertFieldError(parentName, structField.Name)
-						}
-						effField.SetInt(int64(d))
-					}
+// configFieldFunc is called for each struct field with a `config` tag
+type configFieldFunc func(field reflect.Value, structField reflect.StructField, configTag string, parentName string, keyPrefix string) error
 
-				case reflect.Uint, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
-					valueStr, isNil, ok := helpers.ToString(vToSet)
-					if !ok {
-						return newUnableToConvertFieldError(parentName, structField.Name)
-					}
-					if isNil {
-						if !effFieldIsPtr {
-							return newNoDefaultValueForFieldError(parentName, structField.Name)
-						}
-						effField.Set(reflect.Zero(effField.Type()))
-					} else {
-						d, err := parsers.ParseDuration(valueStr)
-						if err != nil || d < 0 {
-							return newUnableToConvertFieldError(parentName, structField.Name)
-						}
-						effField.SetUint(uint64(d))
-					}
+// walkConfigFields calls fn for each field of the struct with a `config` tag. Exported fields without one that are
+// structs, or pointers to structs, are walked recursively, prepending their `configPrefix` tag to the keys. If alloc is
+// true, nested structs are reset and nil pointers allocated, like when they are populated, else nil pointers are skipped.
+func walkConfigFields(v reflect.Value, parentName string, keyPrefix string, alloc bool, fn configFieldFunc) error {
+	visited := map[reflect.Type]struct{}{
+		v.Type(): {},
+	}
+	return walkConfigFieldsRecursive(v, parentName, keyPrefix, alloc, fn, visited)
+}
 
-				default:
-					return newUnableToConvertFieldError(parentName, structField.Name)
-				}
+func walkConfigFieldsRecursive(
+	v reflect.Value, parentName string, keyPrefix string, alloc bool, fn configFieldFunc, visited map[reflect.Type]struct{},
+) error {
+	vType := v.Type()
+	for fIdx := 0; fIdx < v.NumField(); fIdx++ {
+		field := v.Field(fIdx)
+		structField := vType.Field(fIdx)
+		tags := structField.Tag
 
-			} else {
+		configTag := tags.Get("config")
+		if len(configTag) > 0 {
+			err := fn(field, structField, configTag, parentName, keyPrefix)
+			if err != nil {
+				return err
+			}
+			continue
+		}
 
-				// For the rest of the field types
-				switch effField.Kind() {
-				case reflect.String:
-					valueStr, isNil, ok := helpers.ToString(vToSet)
-					if !ok {
-						return newUnableToConvertFieldError(parentName, structField.Name)
-					}
-					if isNil {
-						if !effFieldIsPtr {
-							return newNoDefaultValueForFieldError(parentName, structField.Name)
-						}
-						effField.Set(reflect.Zero(effField.Type()))
-					} else {
-						effField.SetString(valueStr)
-					}
+		// This field has no configuration but handle it if a struct or a pointer to one. Ignore unexported fields.
+		if !structField.IsExported() {
+			continue
+		}
 
-				case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-					valueInt, isNil, overflow, ok := helpers.ToInt(vToSet, effField.Type().Bits())
-					if !ok {
-						return newUnableToConvertFieldError(parentName, structField.Name)
-					}
-					if isNil {
-						if !effFieldIsPtr {
-							return newNoDefaultValueForFieldError(parentName, structField.Name)
-						}
-						effField.Set(reflect.Zero(effField.Type()))
-					} else if overflow {
-						return newOverflowFieldError(parentName, structField.Name)
-					} else {
-						effField.SetInt(valueInt)
-					}
+		// Avoid endless recursion on self-referencing structs
+		fieldType := structField.Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if _, ok := visited[fieldType]; ok {
+			continue
+		}
 
-				case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
-					valueUint, isNil, overflow, ok := helpers.ToUint(vToSet, effField.Type().Bits())
-					if !ok {
-						return newUnableToConvertFieldError(parentName, structField.Name)
-					}
-					if isNil {
-						if !effFieldIsPtr {
-							return newNoDefaultValueForFieldError(parentName, structField.Name)
-						}
-						effField.Set(reflect.Zero(effField.Type()))
-					} else if overflow {
-						return newOverflowFieldError(parentName, structField.Name)
-					} else {
-						effField.SetUint(valueUint)
-					}
+		if alloc {
+			if !field.CanSet() {
+				continue
+			}
+			field = ptrAlloc(field)
+			if field.Kind() != reflect.Struct {
+				continue
+			}
 
-				case reflect.Float32, reflect.Float64:
-					valueFloat, isNil, overflow, ok := helpers.ToFloat(vToSet)
-					if !ok {
-						return newUnableToConvertFieldError(parentName, structField.Name)
-					}
-					if isNil {
-						if !effFieldIsPtr {
-							return newNoDefaultValueForFieldError(parentName, structField.Name)
-						}
-						effField.Set(reflect.Zero(effField.Type()))
-					} else if overflow {
-						return newOverflowFieldError(parentName, structField.Name)
-					} else {
-						effField.SetFloat(valueFloat)
-					}
+			// Create struct object
+			field.Set(reflect.Zero(field.Type()))
+		} else {
+			for field.Kind() == reflect.Pointer && !field.IsNil() {
+				field = field.Elem()
+			}
+			if field.Kind() != reflect.Struct {
+				continue
+			}
+		}
 
-				case reflect.Bool:
-					valueBool, isNil, ok := helpers.ToBool(vToSet)
-					if !ok {
-						return newUnableToConvertFieldError(parentName, structField.Name)
-					}
-					if isNil {
-						if !effFieldIsPtr {
-							return newNoDefaultValueForFieldError(parentName, structField.Name)
-						}
-						effField.Set(reflect.Zero(effField.Type()))
-					} else {
-						effField.SetBool(valueBool)
-					}
+		// Get the prefix to prepend to the keys of the nested struct fields
+		configPrefix, _ := lookupTag(tags, "configPrefix", "config_prefix", "config-prefix")
 
-				case reflect.Struct:
-					// Create struct object
-					effField.Set(reflect.Zero(effField.Type()))
+		// Go deeper
+		visited[fieldType] = struct{}{}
+		err := walkConfigFieldsRecursive(field, parentName+structField.Name+".", keyPrefix+configPrefix, alloc, fn, visited)
+		delete(visited, fieldType)
+		if err != nil {
+			return err
+		}
+	}
 
-					// Go deeper
-					err := cr.fillFieldsRecursive(effField, parentName+structField.Name+".", values)
-					if err != nil {
-						return err
-					}
+	// Done
+	return nil
+}
 
-				case reflect.Slice:
-					fallthrough
-				case reflect.Array:
-					if vToSet == nil {
-						if !effFieldIsPtr {
-							return newNoDefaultValueForFieldError(parentName, structField.Name)
-						}
-						effField.Set(reflect.Zero(effField.Type()))
-
-						// Done
-						break
-					}
+// -----------------------------------------------------------------------------
 
-					// If the target a byte array/slice and the source a string, assume base64 encoded data
-					if effField.Elem().Kind() == reflect.Uint8 && reflect.TypeOf(vToSet).Kind() == reflect.String {
-						data, err := base64.StdEncoding.DecodeString(vToSet.(string))
-						if err != nil {
-							return newUnableToConvertFieldError(parentName, structField.Name)
-						}
-
-						// Check array size or create slice
-						if effField.Kind() == reflect.Slice {
-							l := len(data)
-							effField.Set(reflect.MakeSlice(effField.Type(), l, l))
-						} else {
-							if len(data) != effField.Len() {
-								return fmt.Errorf("base64 encoded data length mismatch for field \"%s%s\"", parentName, structField.Name)
-							}
-						}
-
-						// Copy decoded data
-						copy(effField.Bytes(), data)
-
-						// Done
-						break
-					}
+func (cr *ConfigReader[T]) setValue(field reflect.Value, vToSet interface{}, fi *fieldInfo) error {
+	// Custom decoders take precedence unless a type override was specified
+	if len(fi.typeOverride) == 0 {
+		handled, err := cr.decodeCustom(field, vToSet, fi)
+		if handled || err != nil {
+			return err
+		}
+	}
 
-					// No other conversion supported for now
-					return newUnableToConvertFieldError(parentName, structField.Name)
+	// Get effective field
+	effField := ptrAlloc(field)
+	effFieldIsPtr := field.Kind() == reflect.Pointer
+
+	// Signal error if field cannot be written
+	if !effField.CanSet() {
+		return fmt.Errorf("field \"%s%s\" is not settable", fi.parentName, fi.name)
+	}
+
+	// Special cases
+	if fi.typeOverride == "memory" && !isContainerKind(effField.Kind()) {
+		// Special case for memory type. On slices, arrays and maps, it applies to the elements.
+		switch effField.Kind() {
+		case reflect.Int, reflect.Int32, reflect.Int64:
+			valueStr, isNil, ok := helpers.ToString(vToSet)
+			if !ok {
+				return newUnableToConvertFieldError(fi.parentName, fi.name)
+			}
+			if isNil {
+				if !effFieldIsPtr {
+					return newNoDefaultValueForFieldError(fi.parentName, fi.name)
+				}
+				effField.Set(reflect.Zero(effField.Type()))
+			} else {
+				m, err := parsers.ParseMemorySize(valueStr)
+				if err != nil {
+					return newUnableToConvertFieldError(fi.parentName, fi.name)
+				}
+				if helpers.OverflowCheckUint64(effField.Type(), m) {
+					return newOverflowFieldError(fi.parentName, fi.name)
+				}
+				effField.SetInt(int64(m))
+			}
 
-				default:
-					return newUnableToConvertFieldError(parentName, structField.Name)
+		case reflect.Uint, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			valueStr, isNil, ok := helpers.ToString(vToSet)
+			if !ok {
+				return newUnableToConvertFieldError(fi.parentName, fi.name)
+			}
+			if isNil {
+				if !effFieldIsPtr {
+					return newNoDefaultValueForFieldError(fi.parentName, fi.name)
 				}
+				effField.Set(reflect.Zero(effField.Type()))
+			} else {
+				m, err := parsers.ParseMemorySize(valueStr)
+				if err != nil {
+					return newUnableToConvertFieldError(fi.parentName, fi.name)
+				}
+				if helpers.OverflowCheckUint64(effField.Type(), m) {
+					return newOverflowFieldError(fi.parentName, fi.name)
+				}
+				effField.SetUint(m)
 			}
 
-		} else {
+		default:
+			return newUnableToConvertFieldError(fi.parentName, fi.name)
+		}
+
+	} else if effField.Type() == typeOfTimeDuration {
 
+		//Special case for time duration fields
+		switch effField.Kind() {
+		case reflect.Int, reflect.Int32, reflect.Int64:
 			valueStr, isNil, ok := helpers.ToString(vToSet)
 			if !ok {
-				return newUnableToConvertFieldErrorJSON(parentName, structField.Name)
+				return newUnableToConvertFieldError(fi.parentName, fi.name)
 			}
 			if isNil {
 				if !effFieldIsPtr {
-					return newUnableToConvertFieldErrorJSON(parentName, structField.Name)
+					return newNoDefaultValueForFieldError(fi.parentName, fi.name)
 				}
 				effField.Set(reflect.Zero(effField.Type()))
 			} else {
-				iface := effField.Addr().Interface()
+				d, err := parsers.ParseDuration(valueStr)
+				if err != nil {
+					return newUnableToConvertFieldError(fi.parentName, fi.name)
+				}
+				effField.SetInt(int64(d))
+			}
+
+		case reflect.Uint, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			valueStr, isNil, ok := helpers.ToString(vToSet)
+			if !ok {
+				return newUnableToConvertFieldError(fi.parentName, fi.name)
+			}
+			if isNil {
+				if !effFieldIsPtr {
+					return newNoDefaultValueForFieldError(fi.parentName, fi.name)
+				}
+				effField.Set(reflect.Zero(effField.Type()))
+			} else {
+				d, err := parsers.ParseDuration(valueStr)
+				if err != nil || d < 0 {
+					return newUnableToConvertFieldError(fi.parentName, fi.name)
+				}
+				effField.SetUint(uint64(d))
+			}
+
+		default:
+			return newUnableToConvertFieldError(fi.parentName, fi.name)
+		}
+
+	} else {
+
+		// For the rest of the field types
+		switch effField.Kind() {
+		case reflect.String:
+			valueStr, isNil, ok := helpers.ToString(vToSet)
+			if !ok {
+				return newUnableToConvertFieldError(fi.parentName, fi.name)
+			}
+			if isNil {
+				if !effFieldIsPtr {
+					return newNoDefaultValueForFieldError(fi.parentName, fi.name)
+				}
+				effField.Set(reflect.Zero(effField.Type()))
+			} else {
+				effField.SetString(valueStr)
+			}
+
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			valueInt, isNil, overflow, ok := helpers.ToInt(vToSet, effField.Type().Bits())
+			if !ok {
+				return newUnableToConvertFieldError(fi.parentName, fi.name)
+			}
+			if isNil {
+				if !effFieldIsPtr {
+					return newNoDefaultValueForFieldError(fi.parentName, fi.name)
+				}
+				effField.Set(reflect.Zero(effField.Type()))
+			} else if overflow {
+				return newOverflowFieldError(fi.parentName, fi.name)
+			} else {
+				effField.SetInt(valueInt)
+			}
+
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			valueUint, isNil, overflow, ok := helpers.ToUint(vToSet, effField.Type().Bits())
+			if !ok {
+				return newUnableToConvertFieldError(fi.parentName, fi.name)
+			}
+			if isNil {
+				if !effFieldIsPtr {
+					return newNoDefaultValueForFieldError(fi.parentName, fi.name)
+				}
+				effField.Set(reflect.Zero(effField.Type()))
+			} else if overflow {
+				return newOverflowFieldError(fi.parentName, fi.name)
+			} else {
+				effField.SetUint(valueUint)
+			}
+
+		case reflect.Float32, reflect.Float64:
+			valueFloat, isNil, overflow, ok := helpers.ToFloat(vToSet)
+			if !ok {
+				return newUnableToConvertFieldError(fi.parentName, fi.name)
+			}
+			if isNil {
+				if !effFieldIsPtr {
+					return newNoDefaultValueForFieldError(fi.parentName, fi.name)
+				}
+				effField.Set(reflect.Zero(effField.Type()))
+			} else if overflow {
+				return newOverflowFieldError(fi.parentName, fi.name)
+			} else {
+				effField.SetFloat(valueFloat)
+			}
+
+		case reflect.Bool:
+			valueBool, isNil, ok := helpers.ToBool(vToSet)
+			if !ok {
+				return newUnableToConvertFieldError(fi.parentName, fi.name)
+			}
+			if isNil {
+				if !effFieldIsPtr {
+					return newNoDefaultValueForFieldError(fi.parentName, fi.name)
+				}
+				effField.Set(reflect.Zero(effField.Type()))
+			} else {
+				effField.SetBool(valueBool)
+			}
+
+		case reflect.Struct:
+			// Create struct object
+			effField.Set(reflect.Zero(effField.Type()))
+
+			// Go deeper. If the value is an object, populate the struct from its content
+			var err error
+			if m, ok := helpers.ToStringMap(vToSet); ok {
+				err = cr.fillFieldsRecursive(effField, fi.parentName+fi.name+".", "", m, nil)
+			} else if !fi.isElement {
+				err = cr.fillFieldsRecursive(effField, fi.parentName+fi.name+".", fi.keyPrefix, fi.values, fi.state)
+			} else {
+				// Elements of slices, arrays and maps can only be populated from objects
+				err = newUnableToConvertFieldError(fi.parentName, fi.name)
+			}
+			if err != nil {
+				return err
+			}
 
-				// Unmarshal
-				err := json.Unmarshal([]byte(valueStr), iface)
+		case reflect.Map:
+			if vToSet == nil {
+				if !effFieldIsPtr {
+					return newNoDefaultValueForFieldError(fi.parentName, fi.name)
+				}
+				effField.Set(reflect.Zero(effField.Type()))
+
+				// Done
+				break
+			}
+
+			err := cr.setMapValue(effField, vToSet, fi)
+			if err != nil {
+				return err
+			}
+
+		case reflect.Slice:
+			fallthrough
+		case reflect.Array:
+			if vToSet == nil {
+				if !effFieldIsPtr {
+					return newNoDefaultValueForFieldError(fi.parentName, fi.name)
+				}
+				effField.Set(reflect.Zero(effField.Type()))
+
+				// Done
+				break
+			}
+
+			// If the target a byte array/slice and the source a string, assume base64 encoded data
+			if effField.Type().Elem().Kind() == reflect.Uint8 && reflect.TypeOf(vToSet).Kind() == reflect.String {
+				data, err := base64.StdEncoding.DecodeString(vToSet.(string))
 				if err != nil {
-					return err
+					return newUnableToConvertFieldError(fi.parentName, fi.name)
+				}
+
+				// Check array size or create slice
+				if effField.Kind() == reflect.Slice {
+					l := len(data)
+					effField.Set(reflect.MakeSlice(effField.Type(), l, l))
+				} else {
+					if len(data) != effField.Len() {
+						return fmt.Errorf("base64 encoded data length mismatch for field \"%s%s\"", fi.parentName, fi.name)
+					}
 				}
+
+				// Copy decoded data
+				copy(effField.Bytes(), data)
+
+				// Done
+				break
+			}
+
+			// Else convert each element
+			err := cr.setSliceValue(effField, vToSet, fi)
+			if err != nil {
+				return err
 			}
 
+		default:
+			return newUnableToConvertFieldError(fi.parentName, fi.name)
 		}
 	}
 
@@ -383,6 +553,109 @@ func (cr *ConfigReader[T]) fillFieldsRecursive(v reflect.Value, parentName strin
 	return nil
 }
 
+func (cr *ConfigReader[T]) setSliceValue(effField reflect.Value, vToSet interface{}, fi *fieldInfo) error {
+	var items []interface{}
+
+	// Get the list of items from a delimited string or a native array
+	rV := reflect.ValueOf(vToSet)
+	switch rV.Kind() {
+	case reflect.String:
+		s := strings.TrimSpace(rV.String())
+		if len(s) > 0 {
+			sep := fi.separator
+			if len(sep) == 0 {
+				sep = ","
+			}
+
+			parts := strings.Split(s, sep)
+			items = make([]interface{}, len(parts))
+			for idx, part := range parts {
+				items[idx] = strings.TrimSpace(part)
+			}
+		}
+
+	case reflect.Slice:
+		fallthrough
+	case reflect.Array:
+		items = make([]interface{}, rV.Len())
+		for idx := 0; idx < rV.Len(); idx++ {
+			items[idx] = rV.Index(idx).Interface()
+		}
+
+	default:
+		return newUnableToConvertFieldError(fi.parentName, fi.name)
+	}
+
+	// Check array size or create slice
+	if effField.Kind() == reflect.Slice {
+		l := len(items)
+		effField.Set(reflect.MakeSlice(effField.Type(), l, l))
+	} else {
+		if len(items) != effField.Len() {
+			return fmt.Errorf("items count mismatch for field \"%s%s\"", fi.parentName, fi.name)
+		}
+	}
+
+	// Convert each element
+	for idx, item := range items {
+		elemFi := *fi
+		elemFi.name = fmt.Sprintf("%s[%d]", fi.name, idx)
+		elemFi.isElement = true
+
+		err := cr.setValue(effField.Index(idx), item, &elemFi)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Done
+	return nil
+}
+
+func (cr *ConfigReader[T]) setMapValue(effField reflect.Value, vToSet interface{}, fi *fieldInfo) error {
+	// Only objects can be stored in maps
+	m, ok := helpers.ToStringMap(vToSet)
+	if !ok {
+		return newUnableToConvertFieldError(fi.parentName, fi.name)
+	}
+
+	// Create the map
+	effField.Set(reflect.MakeMapWithSize(effField.Type(), len(m)))
+
+	// Convert each key and element
+	keyType := effField.Type().Key()
+	elemType := effField.Type().Elem()
+	for k, v := range m {
+		elemFi := *fi
+		elemFi.name = fmt.Sprintf("%s[%q]", fi.name, k)
+		elemFi.isElement = true
+
+		// Keys are not affected by the tags of the field, like the type or the separator
+		keyFi := fieldInfo{
+			parentName: fi.parentName,
+			name:       elemFi.name,
+			isElement:  true,
+		}
+
+		key := reflect.New(keyType).Elem()
+		err := cr.setValue(key, k, &keyFi)
+		if err != nil {
+			return err
+		}
+
+		elem := reflect.New(elemType).Elem()
+		err = cr.setValue(elem, v, &elemFi)
+		if err != nil {
+			return err
+		}
+
+		effField.SetMapIndex(key, elem)
+	}
+
+	// Done
+	return nil
+}
+
 // -----------------------------------------------------------------------------
 
 func ptrAlloc(v reflect.Value) reflect.Value {
@@ -395,6 +668,10 @@ func ptrAlloc(v reflect.Value) reflect.Value {
 	return v
 }
 
+func isContainerKind(kind reflect.Kind) bool {
+	return kind == reflect.Slice || kind == reflect.Array || kind == reflect.Map
+}
+
 func isStructOrPtrToStruct(v reflect.Value) bool {
 	vType := v.Type()
 	for vType.Kind() == reflect.Pointer {
@@ -403,6 +680,15 @@ func isStructOrPtrToStruct(v reflect.Value) bool {
 	return vType.Kind() == reflect.Struct
 }
 
+func lookupTag(tags reflect.StructTag, names ...string) (string, bool) {
+	for _, name := range names {
+		if value, ok := tags.Lookup(name); ok {
+			return value, true
+		}
+	}
+	return "", false
+}
+
 func newUnableToConvertFieldError(parentName string, structFieldName string) error {
 	return fmt.Errorf("unable to convert value for field \"%s%s\"", parentName, structFieldName)
 }
@@ -0,0 +1,79 @@
+package configreader
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// -----------------------------------------------------------------------------
+
+// Holder keeps the current configuration settings and allows safe concurrent access to them. Attach it to a
+// configuration reader with WithHolder and it will be updated on each load, including reloads done by a Monitor.
+//
+// The zero value is ready to use.
+type Holder[T any] struct {
+	current atomic.Pointer[T]
+
+	mtx         sync.Mutex
+	subscribers map[chan *T]struct{}
+}
+
+// -----------------------------------------------------------------------------
+
+// NewHolder creates a new configuration settings holder
+func NewHolder[T any]() *Holder[T] {
+	return &Holder[T]{
+		subscribers: make(map[chan *T]struct{}),
+	}
+}
+
+// Get returns the current configuration settings or nil if they were not loaded yet.
+//
+// NOTE: The returned settings must be treated as read-only because they are shared with other readers.
+func (h *Holder[T]) Get() *T {
+	return h.current.Load()
+}
+
+// Subscribe returns a channel that receives the new settings each time they change, and a function to call in order
+// to stop receiving them. If the subscriber is slow, intermediate settings are skipped and only the latest is
+// delivered.
+func (h *Holder[T]) Subscribe() (<-chan *T, func()) {
+	ch := make(chan *T, 1)
+
+	h.mtx.Lock()
+	if h.subscribers == nil {
+		h.subscribers = make(map[chan *T]struct{})
+	}
+	h.subscribers[ch] = struct{}{}
+	h.mtx.Unlock()
+
+	unsubscribe := func() {
+		h.mtx.Lock()
+		defer h.mtx.Unlock()
+
+		if _, ok := h.subscribers[ch]; ok {
+			delete(h.subscribers, ch)
+			close(ch)
+		}
+	}
+
+	// Done
+	return ch, unsubscribe
+}
+
+func (h *Holder[T]) set(settings *T) {
+	// Store and notify under the lock so concurrent updates reach subscribers in the same order they are stored
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	h.current.Store(settings)
+
+	for ch := range h.subscribers {
+		// Discard the pending settings, if any, so the channel never blocks
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- settings
+	}
+}
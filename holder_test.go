@@ -0,0 +1,95 @@
+package configreader_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mxmauro/configreader"
+	"github.com/mxmauro/configreader/loader"
+	"github.com/mxmauro/configreader/model"
+)
+
+// -----------------------------------------------------------------------------
+
+type HolderTest struct {
+	Value int `config:"HOLDER_TEST_VALUE"`
+}
+
+// -----------------------------------------------------------------------------
+
+func TestHolder(t *testing.T) {
+	var value int32 = 1
+
+	holder := configreader.NewHolder[HolderTest]()
+	if holder.Get() != nil {
+		t.Fatalf("holder must be empty before loading")
+	}
+
+	settingsMonitor := configreader.NewMonitor[HolderTest](100*time.Millisecond, nil)
+	defer settingsMonitor.Destroy()
+
+	// Load configuration
+	_, err := configreader.New[HolderTest]().
+		WithLoader(loader.NewCallback().WithCallback(func(_ context.Context) (model.Values, error) {
+			return model.Values{
+				"HOLDER_TEST_VALUE": atomic.LoadInt32(&value),
+			}, nil
+		})).
+		WithHolder(holder).
+		WithMonitor(settingsMonitor).
+		Load(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if holder.Get() == nil || holder.Get().Value != 1 {
+		t.Fatalf("unexpected settings in holder")
+	}
+
+	ch, unsubscribe := holder.Subscribe()
+	defer unsubscribe()
+
+	// Change the value and wait for the monitor to update the holder
+	atomic.StoreInt32(&value, 2)
+
+	select {
+	case settings := <-ch:
+		if settings.Value != 2 {
+			t.Fatalf("unexpected value %d (should be 2)", settings.Value)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("settings change not received")
+	}
+
+	if holder.Get().Value != 2 {
+		t.Fatalf("unexpected value %d in holder (should be 2)", holder.Get().Value)
+	}
+}
+
+func TestHolderZeroValue(t *testing.T) {
+	holder := &configreader.Holder[HolderTest]{}
+
+	ch, unsubscribe := holder.Subscribe()
+	defer unsubscribe()
+
+	// Load configuration
+	_, err := configreader.New[HolderTest]().
+		WithLoader(loader.NewMemory().WithData(model.Values{
+			"HOLDER_TEST_VALUE": 3,
+		})).
+		WithHolder(holder).
+		Load(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	select {
+	case settings := <-ch:
+		if settings.Value != 3 {
+			t.Fatalf("unexpected value %d (should be 3)", settings.Value)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("settings change not received")
+	}
+}
@@ -115,7 +115,7 @@ func ToBool(v interface{}) (value bool, isNil bool, ok bool) {
 	case reflect.Int32:
 		fallthrough
 	case reflect.Int64:
-		value = rV.Int() == 0
+		value = rV.Int() != 0
 
 	case reflect.Uint:
 		fallthrough
@@ -128,7 +128,7 @@ func ToBool(v interface{}) (value bool, isNil bool, ok bool) {
 	case reflect.Uint64:
 		fallthrough
 	case reflect.Uintptr:
-		value = rV.Uint() == 0
+		value = rV.Uint() != 0
 
 	case reflect.Float32:
 		fallthrough
@@ -216,6 +216,7 @@ func ToInt(v interface{}, bitSize int) (value int64, isNil bool, overflow bool,
 		value = int64(f)
 
 	default:
+		return
 	}
 
 	// Check overflow
@@ -306,7 +307,7 @@ func ToUint(v interface{}, bitSize int) (value uint64, isNil bool, overflow bool
 	}
 
 	// Check overflow
-	if value > (1<<(bitSize-1))-1 {
+	if bitSize < 64 && value > (1<<bitSize)-1 {
 		overflow = true
 		return
 	}
@@ -0,0 +1,78 @@
+package helpers_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/mxmauro/configreader/internal/helpers"
+)
+
+// -----------------------------------------------------------------------------
+
+func TestToBool(t *testing.T) {
+	for _, tc := range []struct {
+		value    interface{}
+		expected bool
+	}{
+		{1, true},
+		{0, false},
+		{uint8(2), true},
+		{uint64(0), false},
+		{"true", true},
+	} {
+		value, _, ok := helpers.ToBool(tc.value)
+		if !ok || value != tc.expected {
+			t.Fatalf("unexpected result for %v [value=%v] [ok=%v]", tc.value, value, ok)
+		}
+	}
+}
+
+func TestToInt(t *testing.T) {
+	value, _, overflow, ok := helpers.ToInt(int8(-5), 8)
+	if !ok || overflow || value != -5 {
+		t.Fatalf("unexpected result [value=%v] [overflow=%v] [ok=%v]", value, overflow, ok)
+	}
+
+	_, _, overflow, _ = helpers.ToInt(300, 8)
+	if !overflow {
+		t.Fatalf("overflow not detected")
+	}
+
+	_, _, _, ok = helpers.ToInt(struct{}{}, 64)
+	if ok {
+		t.Fatalf("unsupported type accepted")
+	}
+}
+
+func TestToUint(t *testing.T) {
+	value, _, overflow, ok := helpers.ToUint(uint64(math.MaxUint64), 64)
+	if !ok || overflow || value != math.MaxUint64 {
+		t.Fatalf("unexpected result [value=%v] [overflow=%v] [ok=%v]", value, overflow, ok)
+	}
+
+	value, _, overflow, ok = helpers.ToUint(255, 8)
+	if !ok || overflow || value != 255 {
+		t.Fatalf("unexpected result [value=%v] [overflow=%v] [ok=%v]", value, overflow, ok)
+	}
+
+	_, _, overflow, _ = helpers.ToUint(256, 8)
+	if !overflow {
+		t.Fatalf("overflow not detected")
+	}
+}
+
+func TestOverflowCheck(t *testing.T) {
+	int8Type := reflect.TypeOf(int8(0))
+	uint8Type := reflect.TypeOf(uint8(0))
+
+	if helpers.OverflowCheckInt64(int8Type, 100) || !helpers.OverflowCheckInt64(int8Type, 200) {
+		t.Fatalf("unexpected int64 overflow check result")
+	}
+	if helpers.OverflowCheckInt64(uint8Type, 255) || !helpers.OverflowCheckInt64(uint8Type, -1) {
+		t.Fatalf("unexpected int64 overflow check result")
+	}
+	if helpers.OverflowCheckUint64(uint8Type, 255) || !helpers.OverflowCheckUint64(uint8Type, 256) {
+		t.Fatalf("unexpected uint64 overflow check result")
+	}
+}
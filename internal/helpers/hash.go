@@ -0,0 +1,151 @@
+package helpers
+
+import (
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"math"
+	"reflect"
+	"sort"
+)
+
+// -----------------------------------------------------------------------------
+
+type hashVisit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// -----------------------------------------------------------------------------
+
+// HashValue calculates a SHA-512 hash of the provided value by walking through its content. Unlike an encoder, it
+// supports types without exported fields, like time.Location or regexp.Regexp, by using their textual representation.
+// Pointers, maps and slices referencing themselves are hashed only once to avoid endless recursion.
+func HashValue(v interface{}) [64]byte {
+	h := sha512.New()
+	hashRecursive(h, reflect.ValueOf(v), make(map[hashVisit]struct{}))
+
+	var ret [64]byte
+	copy(ret[:], h.Sum(nil))
+	return ret
+}
+
+// -----------------------------------------------------------------------------
+
+func hashRecursive(h hash.Hash, v reflect.Value, visited map[hashVisit]struct{}) {
+	var buf [8]byte
+
+	if !v.IsValid() {
+		_, _ = h.Write([]byte{0})
+		return
+	}
+	_, _ = h.Write([]byte{byte(v.Kind())})
+
+	// Use the textual representation if available
+	if s, ok := TextOf(v, false); ok {
+		hashString(h, s)
+		return
+	}
+
+	// Check for cycles. Only the current path is tracked, so values shared by several fields are hashed each time.
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice:
+		if !v.IsNil() {
+			visit := hashVisit{
+				ptr: v.Pointer(),
+				typ: v.Type(),
+			}
+			if _, ok := visited[visit]; ok {
+				_, _ = h.Write([]byte{2})
+				return
+			}
+			visited[visit] = struct{}{}
+			defer delete(visited, visit)
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		fallthrough
+	case reflect.Interface:
+		if v.IsNil() {
+			_, _ = h.Write([]byte{0})
+		} else {
+			_, _ = h.Write([]byte{1})
+			hashRecursive(h, v.Elem(), visited)
+		}
+
+	case reflect.Struct:
+		for idx := 0; idx < v.NumField(); idx++ {
+			if v.Type().Field(idx).IsExported() {
+				hashRecursive(h, v.Field(idx), visited)
+			}
+		}
+
+	case reflect.Slice:
+		if v.IsNil() {
+			_, _ = h.Write([]byte{0})
+			return
+		}
+		fallthrough
+	case reflect.Array:
+		binary.LittleEndian.PutUint64(buf[:], uint64(v.Len()))
+		_, _ = h.Write(buf[:])
+		for idx := 0; idx < v.Len(); idx++ {
+			hashRecursive(h, v.Index(idx), visited)
+		}
+
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		binary.LittleEndian.PutUint64(buf[:], uint64(len(keys)))
+		_, _ = h.Write(buf[:])
+		for _, key := range keys {
+			hashRecursive(h, key, visited)
+			hashRecursive(h, v.MapIndex(key), visited)
+		}
+
+	case reflect.String:
+		hashString(h, v.String())
+
+	case reflect.Bool:
+		if v.Bool() {
+			_, _ = h.Write([]byte{1})
+		} else {
+			_, _ = h.Write([]byte{0})
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		binary.LittleEndian.PutUint64(buf[:], uint64(v.Int()))
+		_, _ = h.Write(buf[:])
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		binary.LittleEndian.PutUint64(buf[:], v.Uint())
+		_, _ = h.Write(buf[:])
+
+	case reflect.Float32, reflect.Float64:
+		binary.LittleEndian.PutUint64(buf[:], math.Float64bits(v.Float()))
+		_, _ = h.Write(buf[:])
+
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		binary.LittleEndian.PutUint64(buf[:], math.Float64bits(real(c)))
+		_, _ = h.Write(buf[:])
+		binary.LittleEndian.PutUint64(buf[:], math.Float64bits(imag(c)))
+		_, _ = h.Write(buf[:])
+
+	default:
+		// Channels, functions and unsafe pointers are not part of the configuration
+	}
+}
+
+func hashString(h hash.Hash, s string) {
+	var buf [8]byte
+
+	binary.LittleEndian.PutUint64(buf[:], uint64(len(s)))
+	_, _ = h.Write(buf[:])
+	_, _ = h.Write([]byte(s))
+}
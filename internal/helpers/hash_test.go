@@ -0,0 +1,86 @@
+package helpers_test
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/mxmauro/configreader/internal/helpers"
+)
+
+// -----------------------------------------------------------------------------
+
+type hashTest struct {
+	Name     string
+	Pattern  *regexp.Regexp
+	Location *time.Location
+	Limits   map[string]int
+}
+
+type hashCycleTest struct {
+	Name string
+	Next *hashCycleTest
+}
+
+// -----------------------------------------------------------------------------
+
+func TestHashValueEqual(t *testing.T) {
+	v1 := newHashTest(t, "^a+$", "UTC")
+	v2 := newHashTest(t, "^a+$", "UTC")
+
+	if helpers.HashValue(v1) != helpers.HashValue(v2) {
+		t.Fatalf("equal values must have the same hash")
+	}
+}
+
+func TestHashValueChanges(t *testing.T) {
+	hash := helpers.HashValue(newHashTest(t, "^a+$", "UTC"))
+
+	if helpers.HashValue(newHashTest(t, "^b+$", "UTC")) == hash {
+		t.Fatalf("regular expression change not detected")
+	}
+	if helpers.HashValue(newHashTest(t, "^a+$", "America/New_York")) == hash {
+		t.Fatalf("location change not detected")
+	}
+}
+
+func TestHashValueMapOrder(t *testing.T) {
+	m1 := make(map[string]int)
+	m2 := make(map[string]int)
+	for idx := 0; idx < 100; idx++ {
+		m1[string(rune('A'+idx))] = idx
+		m2[string(rune('A'+99-idx))] = 99 - idx
+	}
+
+	if helpers.HashValue(m1) != helpers.HashValue(m2) {
+		t.Fatalf("map order must not affect the hash")
+	}
+}
+
+func TestHashValueCycle(t *testing.T) {
+	v := &hashCycleTest{
+		Name: "a",
+	}
+	v.Next = v
+
+	// Must not recurse endlessly
+	_ = helpers.HashValue(v)
+}
+
+// -----------------------------------------------------------------------------
+
+func newHashTest(t *testing.T, pattern string, location string) *hashTest {
+	loc, err := time.LoadLocation(location)
+	if err != nil {
+		t.Skipf("unable to load location [err=%v]", err)
+	}
+	return &hashTest{
+		Name:     "test",
+		Pattern:  regexp.MustCompile(pattern),
+		Location: loc,
+		Limits: map[string]int{
+			"cpu":    2,
+			"memory": 512,
+		},
+	}
+}
@@ -0,0 +1,93 @@
+package helpers
+
+import (
+	"strings"
+
+	"github.com/mxmauro/configreader/model"
+)
+
+// -----------------------------------------------------------------------------
+
+// LookupValue finds the value associated to the given key. If not found and the key contains dots, nested objects
+// are traversed, i.e. "db.host" looks for the "host" value inside the "db" object.
+func LookupValue(values model.Values, key string) (interface{}, bool) {
+	v, ok := values[key]
+	if ok || !strings.Contains(key, ".") {
+		return v, ok
+	}
+
+	// Traverse nested objects
+	var current interface{} = map[string]interface{}(values)
+	for _, part := range strings.Split(key, ".") {
+		m, isMap := ToStringMap(current)
+		if !isMap {
+			return nil, false
+		}
+		current, ok = m[part]
+		if !ok {
+			return nil, false
+		}
+	}
+
+	// Done
+	return current, true
+}
+
+// CollectPrefixedValues returns an object containing all the values whose key starts with the given prefix. The
+// prefix is removed from the returned keys.
+func CollectPrefixedValues(values model.Values, prefix string) (map[string]interface{}, bool) {
+	ret := make(map[string]interface{})
+	for k, v := range values {
+		if len(k) > len(prefix) && strings.HasPrefix(k, prefix) {
+			ret[k[len(prefix):]] = v
+		}
+	}
+	if len(ret) == 0 {
+		return nil, false
+	}
+	return ret, true
+}
+
+// FlattenValues returns a copy of the provided values where the content of nested objects is also added as top-level
+// keys. Flattened key names are built by joining the path using the separator and converting it to uppercase, i.e.
+// {"db": {"host": "x"}} adds a "DB_HOST" key if the separator is an underscore.
+func FlattenValues(values model.Values, separator string) model.Values {
+	ret := make(model.Values, len(values))
+	for k, v := range values {
+		ret[k] = v
+	}
+	for k, v := range values {
+		if m, ok := ToStringMap(v); ok {
+			flattenRecursive(ret, strings.ToUpper(k)+separator, m, separator)
+		}
+	}
+	return ret
+}
+
+// ToStringMap returns the provided value as a map if it is an object
+func ToStringMap(v interface{}) (map[string]interface{}, bool) {
+	switch _v := v.(type) {
+	case map[string]interface{}:
+		return _v, true
+	case model.Values:
+		return _v, true
+	}
+	return nil, false
+}
+
+// -----------------------------------------------------------------------------
+
+func flattenRecursive(dst model.Values, prefix string, values map[string]interface{}, separator string) {
+	for k, v := range values {
+		key := prefix + strings.ToUpper(k)
+
+		// Do not overwrite keys already present at the top-level
+		if _, ok := dst[key]; !ok {
+			dst[key] = v
+		}
+
+		if m, ok := ToStringMap(v); ok {
+			flattenRecursive(dst, key+separator, m, separator)
+		}
+	}
+}
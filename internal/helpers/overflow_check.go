@@ -12,23 +12,23 @@ func OverflowCheckInt64(t reflect.Type, value int64) bool {
 	case reflect.Int:
 		if t.Size() == 4 {
 			if value < math.MinInt32 || value > math.MaxInt32 {
-				return false
+				return true
 			}
 		}
 
 	case reflect.Int8:
 		if value < math.MinInt8 || value > math.MaxInt8 {
-			return false
+			return true
 		}
 
 	case reflect.Int16:
 		if value < math.MinInt16 || value > math.MaxInt16 {
-			return false
+			return true
 		}
 
 	case reflect.Int32:
 		if value < math.MinInt32 || value > math.MaxInt32 {
-			return false
+			return true
 		}
 
 	case reflect.Int64:
@@ -37,35 +37,35 @@ func OverflowCheckInt64(t reflect.Type, value int64) bool {
 		fallthrough
 	case reflect.Uintptr:
 		if value < 0 {
-			return false
+			return true
 		}
 		if t.Size() == 4 {
 			if value > math.MaxUint32 {
-				return false
+				return true
 			}
 		}
 
 	case reflect.Uint8:
 		if value < 0 || value > math.MaxUint8 {
-			return false
+			return true
 		}
 
 	case reflect.Uint16:
 		if value < 0 || value > math.MaxUint16 {
-			return false
+			return true
 		}
 
 	case reflect.Uint32:
 		if value < 0 || value > math.MaxUint32 {
-			return false
+			return true
 		}
 
 	case reflect.Uint64:
 		if value < 0 {
-			return false
+			return true
 		}
 	}
-	return true
+	return false
 }
 
 func OverflowCheckUint64(t reflect.Type, value uint64) bool {
@@ -73,28 +73,28 @@ func OverflowCheckUint64(t reflect.Type, value uint64) bool {
 	case reflect.Int:
 		if t.Size() == 4 {
 			if value > math.MaxInt32 {
-				return false
+				return true
 			}
 		}
 
 	case reflect.Int8:
 		if value > math.MaxInt8 {
-			return false
+			return true
 		}
 
 	case reflect.Int16:
 		if value > math.MaxInt16 {
-			return false
+			return true
 		}
 
 	case reflect.Int32:
 		if value > math.MaxInt32 {
-			return false
+			return true
 		}
 
 	case reflect.Int64:
 		if value > math.MaxInt64 {
-			return false
+			return true
 		}
 
 	case reflect.Uint:
@@ -102,26 +102,26 @@ func OverflowCheckUint64(t reflect.Type, value uint64) bool {
 	case reflect.Uintptr:
 		if t.Size() == 4 {
 			if value > math.MaxUint32 {
-				return false
+				return true
 			}
 		}
 
 	case reflect.Uint8:
 		if value > math.MaxUint8 {
-			return false
+			return true
 		}
 
 	case reflect.Uint16:
 		if value > math.MaxUint16 {
-			return false
+			return true
 		}
 
 	case reflect.Uint32:
 		if value > math.MaxUint32 {
-			return false
+			return true
 		}
 
 	case reflect.Uint64:
 	}
-	return true
+	return false
 }
@@ -0,0 +1,58 @@
+package helpers
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+)
+
+// -----------------------------------------------------------------------------
+
+var (
+	typeOfTextMarshaler = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	typeOfStringer      = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+)
+
+// -----------------------------------------------------------------------------
+
+// TextOf returns the textual representation of a value implementing encoding.TextMarshaler or, if it is a struct,
+// fmt.Stringer. If anyStringer is false, fmt.Stringer is only used for structs without exported fields, like
+// regexp.Regexp, whose content cannot be inspected otherwise.
+func TextOf(v reflect.Value, anyStringer bool) (string, bool) {
+	if !v.IsValid() || !v.CanInterface() {
+		return "", false
+	}
+
+	// Get an addressable value, so methods with pointer receivers can be called
+	if !v.CanAddr() {
+		ptr := reflect.New(v.Type())
+		ptr.Elem().Set(v)
+		v = ptr.Elem()
+	}
+	ptr := v.Addr()
+
+	if ptr.Type().Implements(typeOfTextMarshaler) {
+		text, err := ptr.Interface().(encoding.TextMarshaler).MarshalText()
+		if err == nil {
+			return string(text), true
+		}
+	}
+
+	if v.Kind() == reflect.Struct && ptr.Type().Implements(typeOfStringer) && (anyStringer || !hasExportedFields(v.Type())) {
+		return ptr.Interface().(fmt.Stringer).String(), true
+	}
+
+	// Done
+	return "", false
+}
+
+// -----------------------------------------------------------------------------
+
+func hasExportedFields(t reflect.Type) bool {
+	for idx := 0; idx < t.NumField(); idx++ {
+		if t.Field(idx).IsExported() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package configreader
+
+import (
+	"reflect"
+	"strings"
+)
+
+// -----------------------------------------------------------------------------
+
+// configKeys contains the keys referenced by the `config` tags of a struct
+type configKeys struct {
+	keys     map[string]struct{}
+	prefixes []string
+}
+
+// -----------------------------------------------------------------------------
+
+func collectConfigKeys(t reflect.Type) *configKeys {
+	keys := &configKeys{
+		keys:     make(map[string]struct{}),
+		prefixes: make([]string, 0),
+	}
+	keys.collect(t, "", make(map[reflect.Type]struct{}))
+	return keys
+}
+
+func (k *configKeys) contains(key string) bool {
+	if _, ok := k.keys[key]; ok {
+		return true
+	}
+	for _, prefix := range k.prefixes {
+		if len(key) > len(prefix) && strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (k *configKeys) collect(t reflect.Type, keyPrefix string, visited map[reflect.Type]struct{}) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return
+	}
+
+	// Avoid endless recursion on self-referencing structs
+	if _, ok := visited[t]; ok {
+		return
+	}
+	visited[t] = struct{}{}
+	defer delete(visited, t)
+
+	_ = walkConfigFields(reflect.New(t).Elem(), "", keyPrefix, true, func(_ reflect.Value, structField reflect.StructField, configTag string, _ string, keyPrefix string) error {
+		if strings.HasSuffix(configTag, "*") {
+			k.prefixes = append(k.prefixes, keyPrefix+configTag[:len(configTag)-1])
+		} else {
+			k.keys[keyPrefix+configTag] = struct{}{}
+		}
+
+		// Struct fields not populated from an object use the same keys as their parent
+		k.collect(structField.Type, keyPrefix, visited)
+		return nil
+	})
+}
@@ -0,0 +1,255 @@
+package loader
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/mxmauro/configreader/internal/helpers"
+	"github.com/mxmauro/configreader/model"
+)
+
+// -----------------------------------------------------------------------------
+
+// CachedLoader wraps a loader and keeps a local copy of the last successfully loaded values to use if the wrapped
+// loader fails
+type CachedLoader struct {
+	l model.Loader
+
+	filename      string
+	encryptionKey []byte
+	maxStaleness  time.Duration
+
+	mtx       sync.Mutex
+	cachedAt  time.Time
+	savedHash [64]byte
+	savedAt   time.Time
+
+	err error
+}
+
+type cacheContent struct {
+	SavedAt time.Time    `json:"saved_at"`
+	Values  model.Values `json:"values"`
+}
+
+// -----------------------------------------------------------------------------
+
+// NewCache creates a new caching loader that wraps the provided one
+func NewCache(l model.Loader) *CachedLoader {
+	return &CachedLoader{
+		l: l,
+	}
+}
+
+// WithFilename sets the name of the file where the values are stored
+func (l *CachedLoader) WithFilename(filename string) *CachedLoader {
+	if l.err == nil {
+		filename, l.err = expandAndNormalizeFilename(filename)
+		if l.err == nil {
+			l.filename = filename
+		}
+	}
+	return l
+}
+
+// WithEncryptionKey sets the AES key used to encrypt the cache file. The key must be 16, 24 or 32 bytes long.
+func (l *CachedLoader) WithEncryptionKey(key []byte) *CachedLoader {
+	if l.err == nil {
+		switch len(key) {
+		case 16, 24, 32:
+			l.encryptionKey = make([]byte, len(key))
+			copy(l.encryptionKey, key)
+		default:
+			l.err = errors.New("invalid encryption key length")
+		}
+	}
+	return l
+}
+
+// WithMaxStaleness sets the maximum age of the cached values that can be used if the wrapped loader fails. Zero means
+// no limit.
+func (l *CachedLoader) WithMaxStaleness(maxStaleness time.Duration) *CachedLoader {
+	if l.err == nil {
+		if maxStaleness >= 0 {
+			l.maxStaleness = maxStaleness
+		} else {
+			l.err = errors.New("invalid max staleness")
+		}
+	}
+	return l
+}
+
+// Load loads the content from the wrapped loader and updates the cache. If the wrapped loader fails, the cached
+// values are returned if not too old.
+func (l *CachedLoader) Load(ctx context.Context) (model.Values, error) {
+	// If an error was set by a With... function, return it
+	if l.err != nil {
+		return nil, l.err
+	}
+	if len(l.filename) == 0 {
+		return nil, errors.New("filename not set")
+	}
+	if len(l.encryptionKey) == 0 {
+		return nil, errors.New("encryption key not set")
+	}
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	// Load values from the wrapped loader
+	values, err := l.l.Load(ctx)
+	if err == nil {
+		l.cachedAt = time.Time{}
+
+		// Update the cache if the values changed since they were saved. If a maximum staleness is set, also refresh
+		// it before the saved copy becomes too old to be used. A failure here must not prevent using the fresh values.
+		hash := helpers.HashValue(values)
+		if hash != l.savedHash || (l.maxStaleness > 0 && time.Since(l.savedAt) > l.maxStaleness/2) {
+			savedAt := time.Now().UTC()
+			if l.save(values, savedAt) == nil {
+				l.savedHash = hash
+				l.savedAt = savedAt
+			}
+		}
+
+		// Done
+		return values, nil
+	}
+
+	// The wrapped loader failed, try to use the cached values
+	content, cacheErr := l.load()
+	if cacheErr != nil {
+		if errors.Is(cacheErr, os.ErrNotExist) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w [cache-err=%v]", err, cacheErr)
+	}
+	if l.maxStaleness > 0 && time.Since(content.SavedAt) > l.maxStaleness {
+		return nil, fmt.Errorf("%w [cache-err=cached values are too old]", err)
+	}
+	l.cachedAt = content.SavedAt
+
+	// Done
+	return content.Values, nil
+}
+
+// Unwrap returns the wrapped loader
+func (l *CachedLoader) Unwrap() model.Loader {
+	return l.l
+}
+
+// Watch watches the wrapped loader if it implements model.Watcher, else returns nil
+func (l *CachedLoader) Watch(ctx context.Context) <-chan struct{} {
+	return watchLoader(ctx, l.l)
+}
+
+// CachedAt returns the time the values returned by the last load were stored in the cache or false if they were
+// provided by the wrapped loader
+func (l *CachedLoader) CachedAt() (time.Time, bool) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	return l.cachedAt, !l.cachedAt.IsZero()
+}
+
+// -----------------------------------------------------------------------------
+
+func (l *CachedLoader) save(values model.Values, savedAt time.Time) error {
+	plaintext, err := json.Marshal(cacheContent{
+		SavedAt: savedAt,
+		Values:  values,
+	})
+	if err != nil {
+		return err
+	}
+
+	aead, err := l.newAEAD()
+	if err != nil {
+		return err
+	}
+
+	// Encrypt content prepending the nonce
+	nonce := make([]byte, aead.NonceSize())
+	_, err = io.ReadFull(rand.Reader, nonce)
+	if err != nil {
+		return err
+	}
+	data := aead.Seal(nonce, nonce, plaintext, nil)
+
+	// Write to a temporary file and rename it so the cache is never left partially written
+	tempFile, err := os.CreateTemp(filepath.Dir(l.filename), filepath.Base(l.filename)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tempFilename := tempFile.Name()
+	_, err = tempFile.Write(data)
+	if err == nil {
+		err = tempFile.Sync()
+	}
+	err2 := tempFile.Close()
+	if err == nil {
+		err = err2
+	}
+	if err == nil {
+		err = os.Rename(tempFilename, l.filename)
+	}
+	if err != nil {
+		_ = os.Remove(tempFilename)
+		return err
+	}
+
+	// Done
+	return nil
+}
+
+func (l *CachedLoader) load() (*cacheContent, error) {
+	data, err := os.ReadFile(l.filename)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := l.newAEAD()
+	if err != nil {
+		return nil, err
+	}
+
+	// Decrypt content
+	if len(data) < aead.NonceSize() {
+		return nil, errors.New("invalid cache file")
+	}
+	plaintext, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, errors.New("unable to decrypt cache file")
+	}
+
+	// Decode values keeping numbers as is
+	content := cacheContent{}
+	decoder := json.NewDecoder(bytes.NewReader(plaintext))
+	decoder.UseNumber()
+	err = decoder.Decode(&content)
+	if err != nil {
+		return nil, err
+	}
+
+	// Done
+	return &content, nil
+}
+
+func (l *CachedLoader) newAEAD() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(l.encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
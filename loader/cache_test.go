@@ -0,0 +1,142 @@
+package loader_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mxmauro/configreader"
+	"github.com/mxmauro/configreader/loader"
+	"github.com/mxmauro/configreader/model"
+)
+
+// -----------------------------------------------------------------------------
+
+type CacheLoaderTest struct {
+	Password string `config:"CACHE_TEST_PASSWORD"`
+	Port     int    `config:"CACHE_TEST_PORT"`
+}
+
+// -----------------------------------------------------------------------------
+
+func TestCacheLoader(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "settings.cache")
+	key := []byte("0123456789abcdef0123456789abcdef")
+
+	failing := atomic.Bool{}
+	source := loader.NewCallback().WithCallback(func(_ context.Context) (model.Values, error) {
+		if failing.Load() {
+			return nil, errors.New("source unavailable")
+		}
+		return model.Values{
+			"CACHE_TEST_PASSWORD": "my-secret-password",
+			"CACHE_TEST_PORT":     8080,
+		}, nil
+	})
+
+	cr := configreader.New[CacheLoaderTest]().
+		WithLoader(loader.NewCache(source).WithFilename(filename).WithEncryptionKey(key).WithMaxStaleness(time.Hour))
+
+	// First load populates the cache
+	_, report, err := cr.LoadWithReport(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if report.Fields["Port"].FromCache || report.Fields["Port"].Source != "Callback" {
+		t.Fatalf("unexpected report [source=%v, from-cache=%v]", report.Fields["Port"].Source,
+			report.Fields["Port"].FromCache)
+	}
+
+	// The cache must be encrypted
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if bytes.Contains(data, []byte("my-secret-password")) {
+		t.Fatalf("cache file is not encrypted")
+	}
+
+	// Now make the source fail and check cached values are used
+	failing.Store(true)
+
+	settings, report, err := cr.LoadWithReport(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if settings.Password != "my-secret-password" || settings.Port != 8080 {
+		t.Fatalf("unexpected settings [password=%v, port=%v]", settings.Password, settings.Port)
+	}
+	if !report.Fields["Port"].FromCache || report.Fields["Port"].CachedAt.IsZero() {
+		t.Fatalf("cached value not flagged in report")
+	}
+
+	// Stale values must not be used
+	_, err = configreader.New[CacheLoaderTest]().
+		WithLoader(loader.NewCache(source).WithFilename(filename).WithEncryptionKey(key).WithMaxStaleness(time.Nanosecond)).
+		Load(context.Background())
+	if err == nil {
+		t.Fatalf("stale cache was used")
+	}
+
+	// A different key must not decrypt the cache
+	_, err = configreader.New[CacheLoaderTest]().
+		WithLoader(loader.NewCache(source).WithFilename(filename).WithEncryptionKey([]byte("fedcba9876543210"))).
+		Load(context.Background())
+	if err == nil {
+		t.Fatalf("cache was decrypted with a wrong key")
+	}
+}
+
+func TestCacheLoaderSkipsUnchangedValues(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "settings.cache")
+	key := []byte("0123456789abcdef")
+
+	port := atomic.Int32{}
+	port.Store(8080)
+	l := loader.NewCache(loader.NewCallback().WithCallback(func(_ context.Context) (model.Values, error) {
+		return model.Values{
+			"CACHE_TEST_PORT": int(port.Load()),
+		}, nil
+	})).WithFilename(filename).WithEncryptionKey(key)
+
+	_, err := l.Load(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	// Loading the same values must not rewrite the cache. Each write uses a new nonce, so the content would differ.
+	_, err = l.Load(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	data2, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if !bytes.Equal(data, data2) {
+		t.Fatalf("cache rewritten with unchanged values")
+	}
+
+	// But changed values must be saved
+	port.Store(9090)
+	_, err = l.Load(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	data2, err = os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if bytes.Equal(data, data2) {
+		t.Fatalf("cache not updated with changed values")
+	}
+}
@@ -3,6 +3,8 @@ package loader
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/mxmauro/configreader/internal/helpers"
 	"github.com/mxmauro/configreader/model"
@@ -10,13 +12,13 @@ import (
 
 // -----------------------------------------------------------------------------
 
-const (
-	parseDataHintIsDotEnv = 0x0001
+var (
+	errUnableToIdentifyFileType = errors.New("unable to identify file type")
 )
 
 // -----------------------------------------------------------------------------
 
-func parseData(data []byte, hint int) (model.Values, error) {
+func parseData(data []byte, f *format) (model.Values, error) {
 	if data == nil {
 		return make(model.Values), nil
 	}
@@ -24,28 +26,44 @@ func parseData(data []byte, hint int) (model.Values, error) {
 	// Normalize content
 	data = helpers.NormalizeEOL(data)
 
-	if (hint&parseDataHintIsDotEnv) == 0 || isDotEnv(data) {
-		// Assume a .env file
-		return parseDotEnv(data)
+	// If the format is unknown, try to guess it
+	if f == nil {
+		f = sniffFormat(data)
+		if f == nil {
+			return nil, errUnableToIdentifyFileType
+		}
 	}
 
+	// Parse
+	return f.parse(data)
+}
+
+func parseJSON(data []byte) (model.Values, error) {
+	var ret model.Values
+
 	data = helpers.RemoveComments(data)
 
-	if isJSON(data) {
-		// Assume a value JSON file
-		var ret model.Values
+	err := json.Unmarshal(data, &ret)
+	if err != nil {
+		return nil, err
+	}
 
-		err := json.Unmarshal(data, &ret)
-		if err != nil {
-			return nil, err
-		}
+	// Done
+	return ret, nil
+}
+
+func sniffJSON(data []byte) bool {
+	return isJSON(helpers.RemoveComments(data))
+}
 
-		// Done
-		return ret, nil
+func sniffDotEnv(data []byte) bool {
+	if !isDotEnv(data) {
+		return false
 	}
 
-	// Unable to identify file type
-	return nil, errors.New("unable to identify file type")
+	// A YAML document with flat key/value pairs in the first line also looks like a .env file
+	_, err := parseDotEnv(data)
+	return err == nil || !isYAML(data)
 }
 
 func isJSON(data []byte) bool {
@@ -60,3 +78,30 @@ func isJSON(data []byte) bool {
 	}
 	return false
 }
+
+func normalizeDecodedValue(v interface{}) interface{} {
+	switch _v := v.(type) {
+	case map[string]interface{}:
+		for k, elem := range _v {
+			_v[k] = normalizeDecodedValue(elem)
+		}
+		return _v
+
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(_v))
+		for k, elem := range _v {
+			m[fmt.Sprintf("%v", k)] = normalizeDecodedValue(elem)
+		}
+		return m
+
+	case []interface{}:
+		for idx, elem := range _v {
+			_v[idx] = normalizeDecodedValue(elem)
+		}
+		return _v
+
+	case time.Time:
+		return _v.Format(time.RFC3339Nano)
+	}
+	return v
+}
@@ -2,12 +2,28 @@ package loader
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
 
 	"github.com/mxmauro/configreader/model"
 )
 
 // -----------------------------------------------------------------------------
 
+var (
+	// ErrSourceNotFound is returned, wrapping the original error, when the source does not exist, like a missing file
+	// or an http resource returning status 404
+	ErrSourceNotFound = errors.New("source not found")
+
+	// ErrSourceUnavailable is returned, wrapping the original error, when the source cannot be reached, like a
+	// connection failure
+	ErrSourceUnavailable = errors.New("source unavailable")
+)
+
+// -----------------------------------------------------------------------------
+
 type errorLoader struct {
 	err error
 }
@@ -17,3 +33,41 @@ type errorLoader struct {
 func (l *errorLoader) Load(_ context.Context) (model.Values, error) {
 	return nil, l.err
 }
+
+// -----------------------------------------------------------------------------
+
+func newSourceNotFoundError(err error) error {
+	return fmt.Errorf("%w [err=%w]", ErrSourceNotFound, err)
+}
+
+func newSourceUnavailableError(err error) error {
+	return fmt.Errorf("%w [err=%w]", ErrSourceUnavailable, err)
+}
+
+// isConnectionError returns true if the error was caused by a failure to reach the source, like a name resolution
+// error or a refused connection. Other failures, like certificate or protocol errors, mean the source is broken or
+// misconfigured and are not considered connection errors.
+func isConnectionError(ctx context.Context, err error) bool {
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+
+	// Ignore errors caused by the caller's context
+	if ctx.Err() != nil {
+		return false
+	}
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	return errors.As(err, &dnsErr) || errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// isTimeoutError returns true if a request timed out while the caller's context is still active
+func isTimeoutError(ctx context.Context, err error) bool {
+	var netErr net.Error
+
+	// Ignore errors caused by the caller's context
+	if ctx.Err() != nil {
+		return false
+	}
+	return errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
+}
@@ -0,0 +1,6 @@
+package loader
+
+// -----------------------------------------------------------------------------
+
+// UnregisterFormat exposes unregisterFormat to the tests, so formats registered by them do not leak into other tests
+var UnregisterFormat = unregisterFormat
@@ -5,7 +5,6 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
-	"strings"
 
 	"github.com/mxmauro/configreader/internal/helpers"
 	"github.com/mxmauro/configreader/model"
@@ -102,14 +101,14 @@ func (l *File) Load(_ context.Context) (model.Values, error) {
 	// Load file
 	content, err := os.ReadFile(l.filename)
 	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, newSourceNotFoundError(err)
+		}
 		return nil, err
 	}
 
 	// Parse data
-	if strings.HasSuffix(l.filename, ".env") {
-		return parseData(content, parseDataHintIsDotEnv)
-	}
-	return parseData(content, 0)
+	return parseData(content, findFormatByExtension(filepath.Ext(l.filename)))
 }
 
 // -----------------------------------------------------------------------------
@@ -0,0 +1,96 @@
+package loader
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// -----------------------------------------------------------------------------
+
+const (
+	fileWatchDebounce = 100 * time.Millisecond
+)
+
+// -----------------------------------------------------------------------------
+
+// Watch returns a channel that receives a signal each time the file changes. The channel is closed when the context
+// is done. If the file cannot be watched, nil is returned.
+//
+// The directory containing the file is watched, instead of the file itself, in order to detect editors replacing the
+// file by renaming a new one and Kubernetes ConfigMap updates done by swapping the "..data" symbolic link.
+func (l *File) Watch(ctx context.Context) <-chan struct{} {
+	if l.err != nil || len(l.filename) == 0 {
+		return nil
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil
+	}
+	err = watcher.Add(filepath.Dir(l.filename))
+	if err != nil {
+		_ = watcher.Close()
+		return nil
+	}
+
+	ch := make(chan struct{}, 1)
+	lastInfo, _ := os.Stat(l.filename)
+
+	go func() {
+		var debounceCh <-chan time.Time
+
+		defer close(ch)
+		defer func() {
+			_ = watcher.Close()
+		}()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case _, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				// Wait until events stop arriving because a single update usually generates several of them
+				debounceCh = time.After(fileWatchDebounce)
+
+			case _, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+
+			case <-debounceCh:
+				debounceCh = nil
+
+				// Check if the file really changed. Stat follows symbolic links, so a swap is also detected.
+				info, _ := os.Stat(l.filename)
+				if !isSameFileInfo(lastInfo, info) {
+					lastInfo = info
+
+					select {
+					case ch <- struct{}{}:
+					default:
+					}
+				}
+			}
+		}
+	}()
+
+	// Done
+	return ch
+}
+
+// -----------------------------------------------------------------------------
+
+func isSameFileInfo(info1 os.FileInfo, info2 os.FileInfo) bool {
+	if info1 == nil || info2 == nil {
+		return info1 == nil && info2 == nil
+	}
+	return os.SameFile(info1, info2) && info1.Size() == info2.Size() && info1.ModTime().Equal(info2.ModTime())
+}
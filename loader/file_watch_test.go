@@ -0,0 +1,166 @@
+package loader_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mxmauro/configreader"
+	"github.com/mxmauro/configreader/loader"
+)
+
+// -----------------------------------------------------------------------------
+
+type FileWatchTest struct {
+	Value int `config:"FILE_WATCH_TEST_VALUE"`
+}
+
+// -----------------------------------------------------------------------------
+
+func TestFileWatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "settings.env")
+	writeFileWatchTest(t, filename, 1)
+
+	ch := loader.NewFile().WithFilename(filename).Watch(ctx)
+	if ch == nil {
+		t.Fatalf("unable to watch file")
+	}
+
+	// Write in place
+	writeFileWatchTest(t, filename, 2)
+	waitForFileWatchSignal(t, ch)
+
+	// Rename and replace, like editors do
+	writeFileWatchTest(t, filename+".tmp", 3)
+	err := os.Rename(filename+".tmp", filename)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	waitForFileWatchSignal(t, ch)
+
+	// Channel must be closed when the context is done
+	cancel()
+	select {
+	case _, ok := <-ch:
+		for ok {
+			_, ok = <-ch
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("channel not closed")
+	}
+}
+
+func TestFileWatchSymlinkSwap(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Mimic the layout of a mounted Kubernetes ConfigMap
+	dir := t.TempDir()
+	createFileWatchConfigMapVersion(t, dir, "..v1", 1)
+	err := os.Symlink("..v1", filepath.Join(dir, "..data"))
+	if err == nil {
+		err = os.Symlink(filepath.Join("..data", "settings.env"), filepath.Join(dir, "settings.env"))
+	}
+	if err != nil {
+		t.Skipf("unable to create symbolic links [err=%v]", err)
+	}
+
+	ch := loader.NewFile().WithFilename(filepath.Join(dir, "settings.env")).Watch(ctx)
+	if ch == nil {
+		t.Fatalf("unable to watch file")
+	}
+
+	// Swap the data link
+	createFileWatchConfigMapVersion(t, dir, "..v2", 2)
+	err = os.Symlink("..v2", filepath.Join(dir, "..data_tmp"))
+	if err == nil {
+		err = os.Rename(filepath.Join(dir, "..data_tmp"), filepath.Join(dir, "..data"))
+	}
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	waitForFileWatchSignal(t, ch)
+}
+
+func TestFileWatchWrapped(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	filename := filepath.Join(t.TempDir(), "settings.env")
+	writeFileWatchTest(t, filename, 1)
+
+	if loader.Optional(loader.NewFile().WithFilename(filename)).Watch(ctx) == nil {
+		t.Fatalf("optional file loader must be watchable")
+	}
+	if loader.NewCache(loader.NewFile().WithFilename(filename)).Watch(ctx) == nil {
+		t.Fatalf("cached file loader must be watchable")
+	}
+	if loader.FirstOf(loader.NewFile().WithFilename(filename), loader.NewMemory()).Watch(ctx) != nil {
+		t.Fatalf("chain with loaders that cannot be watched must not be watchable")
+	}
+}
+
+func TestMonitorWithFileWatch(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "settings.env")
+	writeFileWatchTest(t, filename, 1)
+
+	settingsCh := make(chan *FileWatchTest, 10)
+
+	// Use a long poll interval so only the file watcher can trigger a reload in time
+	settingsMonitor := configreader.NewMonitor[FileWatchTest](time.Hour, func(settings *FileWatchTest, loadErr error) {
+		if loadErr == nil {
+			settingsCh <- settings
+		}
+	})
+	defer settingsMonitor.Destroy()
+
+	_, err := configreader.New[FileWatchTest]().
+		WithLoader(loader.NewFile().WithFilename(filename)).
+		WithMonitor(settingsMonitor).
+		Load(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	writeFileWatchTest(t, filename, 2)
+
+	select {
+	case settings := <-settingsCh:
+		if settings.Value != 2 {
+			t.Fatalf("unexpected value %d (should be 2)", settings.Value)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("settings change not received")
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+func writeFileWatchTest(t *testing.T, filename string, value int) {
+	err := os.WriteFile(filename, []byte("FILE_WATCH_TEST_VALUE="+string(rune('0'+value))+"\n"), 0600)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+}
+
+func createFileWatchConfigMapVersion(t *testing.T, dir string, version string, value int) {
+	err := os.Mkdir(filepath.Join(dir, version), 0700)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	writeFileWatchTest(t, filepath.Join(dir, version, "settings.env"), value)
+}
+
+func waitForFileWatchSignal(t *testing.T, ch <-chan struct{}) {
+	select {
+	case <-ch:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("file change not detected")
+	}
+}
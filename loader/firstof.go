@@ -0,0 +1,89 @@
+package loader
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/mxmauro/configreader/model"
+)
+
+// -----------------------------------------------------------------------------
+
+// FirstOfLoader wraps a chain of loaders where the first one that succeeds provides the values
+type FirstOfLoader struct {
+	loaders []model.Loader
+
+	mtx  sync.Mutex
+	last model.Loader
+}
+
+// -----------------------------------------------------------------------------
+
+// FirstOf creates a loader that executes the provided loaders in order and returns the values of the first one that
+// succeeds. If all of them fail, the returned error wraps every individual error.
+func FirstOf(l ...model.Loader) *FirstOfLoader {
+	return &FirstOfLoader{
+		loaders: l,
+	}
+}
+
+// Load loads the content from the first loader that succeeds
+func (l *FirstOfLoader) Load(ctx context.Context) (model.Values, error) {
+	if len(l.loaders) == 0 {
+		return nil, errors.New("no loaders specified")
+	}
+
+	errs := make([]error, 0, len(l.loaders))
+	for _, _l := range l.loaders {
+		values, err := _l.Load(ctx)
+		if err == nil {
+			l.mtx.Lock()
+			l.last = _l
+			l.mtx.Unlock()
+
+			// Done
+			return values, nil
+		}
+		errs = append(errs, err)
+
+		// Stop if the context was canceled
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	// All loaders failed
+	return nil, errors.Join(errs...)
+}
+
+// Unwrap returns the loader that provided the values in the last successful load
+func (l *FirstOfLoader) Unwrap() model.Loader {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	return l.last
+}
+
+// Watch returns a channel that receives a signal each time the source of any of the loaders changes or nil if some of
+// them cannot be watched
+func (l *FirstOfLoader) Watch(ctx context.Context) <-chan struct{} {
+	if len(l.loaders) == 0 {
+		return nil
+	}
+
+	watchCtx, cancelWatchCtx := context.WithCancel(ctx)
+
+	chs := make([]<-chan struct{}, 0, len(l.loaders))
+	for _, _l := range l.loaders {
+		ch := watchLoader(watchCtx, _l)
+		if ch == nil {
+			cancelWatchCtx()
+			return nil
+		}
+		chs = append(chs, ch)
+	}
+
+	// Done
+	return mergeWatchChannels(chs, cancelWatchCtx)
+}
@@ -0,0 +1,163 @@
+package loader
+
+import (
+	"mime"
+	"strings"
+	"sync"
+
+	"github.com/mxmauro/configreader/model"
+)
+
+// -----------------------------------------------------------------------------
+
+// FormatSniffer returns true if the provided content looks like the format it handles
+type FormatSniffer func(data []byte) bool
+
+// FormatParser converts the provided content into a set of values
+type FormatParser func(data []byte) (model.Values, error)
+
+type format struct {
+	name       string
+	extensions []string
+	mimeTypes  []string
+	sniff      FormatSniffer
+	parse      FormatParser
+}
+
+// -----------------------------------------------------------------------------
+
+var (
+	formatsMtx = sync.RWMutex{}
+	formats    = make([]*format, 0)
+)
+
+// -----------------------------------------------------------------------------
+
+func init() {
+	RegisterFormat("json", []string{".json"}, sniffJSON, parseJSON, "application/json", "text/json")
+	RegisterFormat("dotenv", []string{".env"}, sniffDotEnv, parseDotEnv)
+	RegisterFormat("yaml", []string{".yaml", ".yml"}, isYAML, parseYAML,
+		"application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml")
+	RegisterFormat("toml", []string{".toml"}, nil, parseTOML, "application/toml")
+	RegisterFormat("ini", []string{".ini"}, nil, parseINI)
+}
+
+// -----------------------------------------------------------------------------
+
+// RegisterFormat adds a new content format, or replaces an existing one with the same name, to the list of formats
+// recognized by the File, Http and Memory loaders.
+//
+// Extensions must include the leading dot. The sniff function is optional and, if provided, is used to guess the format
+// of content whose extension and content type are unknown. Formats are sniffed in the same order they were registered.
+// Optional mime types are matched against the Content-Type header returned by http servers.
+func RegisterFormat(name string, extensions []string, sniff FormatSniffer, parse FormatParser, mimeTypes ...string) {
+	if len(name) == 0 {
+		panic("loader: format name not set")
+	}
+	if parse == nil {
+		panic("loader: format parser not set")
+	}
+
+	f := &format{
+		name:       name,
+		extensions: make([]string, len(extensions)),
+		mimeTypes:  make([]string, len(mimeTypes)),
+		sniff:      sniff,
+		parse:      parse,
+	}
+	for idx, ext := range extensions {
+		f.extensions[idx] = strings.ToLower(ext)
+	}
+	for idx, mimeType := range mimeTypes {
+		f.mimeTypes[idx] = strings.ToLower(mimeType)
+	}
+
+	formatsMtx.Lock()
+	defer formatsMtx.Unlock()
+
+	// Replace if a format with the same name already exists
+	for idx := range formats {
+		if formats[idx].name == name {
+			formats[idx] = f
+			return
+		}
+	}
+	formats = append(formats, f)
+}
+
+// unregisterFormat removes a previously registered content format
+func unregisterFormat(name string) {
+	formatsMtx.Lock()
+	defer formatsMtx.Unlock()
+
+	for idx := range formats {
+		if formats[idx].name == name {
+			formats = append(formats[:idx], formats[idx+1:]...)
+			return
+		}
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+func findFormatByExtension(ext string) *format {
+	if len(ext) == 0 {
+		return nil
+	}
+	ext = strings.ToLower(ext)
+
+	formatsMtx.RLock()
+	defer formatsMtx.RUnlock()
+
+	for _, f := range formats {
+		for _, e := range f.extensions {
+			if e == ext {
+				return f
+			}
+		}
+	}
+	return nil
+}
+
+func findFormatByContentType(contentType string) *format {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil
+	}
+
+	formatsMtx.RLock()
+	defer formatsMtx.RUnlock()
+
+	f := findFormatByMimeTypeLocked(mediaType)
+	if f == nil {
+		// Check for structured syntax suffixes like application/vnd.something+json
+		plusIdx := strings.LastIndexByte(mediaType, '+')
+		if plusIdx > 0 {
+			f = findFormatByMimeTypeLocked("application/" + mediaType[plusIdx+1:])
+		}
+	}
+	return f
+}
+
+func findFormatByMimeTypeLocked(mediaType string) *format {
+	for _, f := range formats {
+		for _, m := range f.mimeTypes {
+			if m == mediaType {
+				return f
+			}
+		}
+	}
+	return nil
+}
+
+func sniffFormat(data []byte) *format {
+	formatsMtx.RLock()
+	defer formatsMtx.RUnlock()
+
+	for _, f := range formats {
+		if f.sniff != nil && f.sniff(data) {
+			return f
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package loader_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mxmauro/configreader"
+	"github.com/mxmauro/configreader/internal/testhelpers"
+	"github.com/mxmauro/configreader/loader"
+	"github.com/mxmauro/configreader/model"
+	"gopkg.in/yaml.v3"
+)
+
+// -----------------------------------------------------------------------------
+
+type PropertiesFormatTest struct {
+	Value int `config:"TEST_VALUE"`
+}
+
+// -----------------------------------------------------------------------------
+
+func TestRegisterFormat(t *testing.T) {
+	// Register a minimal Java-like properties format
+	loader.RegisterFormat("properties", []string{".properties"}, nil, func(data []byte) (model.Values, error) {
+		ret := make(model.Values)
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if len(line) == 0 || line[0] == '!' {
+				continue
+			}
+			k, v, _ := strings.Cut(line, "=")
+			ret[strings.ToUpper(strings.ReplaceAll(k, ".", "_"))] = v
+		}
+		return ret, scanner.Err()
+	})
+	t.Cleanup(func() {
+		loader.UnregisterFormat("properties")
+	})
+
+	// Create a new temporary file
+	file, err := os.CreateTemp("", "cr*.properties")
+	if err != nil {
+		t.Fatalf("unable to create temporary file [err=%v]", err)
+	}
+	defer func() {
+		filename := file.Name()
+
+		_ = file.Close()
+		_ = os.Remove(filename)
+	}()
+
+	_, err = file.WriteString("! Sample\ntest.value=12345\n")
+	if err != nil {
+		t.Fatalf("unable to save settings in a file [err=%v]", err)
+	}
+
+	// Load configuration from file
+	var settings *PropertiesFormatTest
+	settings, err = configreader.New[PropertiesFormatTest]().
+		WithLoader(loader.NewFile().WithFilename(file.Name())).
+		Load(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if settings.Value != 12345 {
+		t.Fatalf("settings mismatch")
+	}
+}
+
+func TestHttpLoaderWithContentType(t *testing.T) {
+	content, err := yaml.Marshal(testhelpers.GoodSettingsMap)
+	if err != nil {
+		t.Fatalf("unable to encode good settings [err=%v]", err)
+	}
+
+	// Create a test http server that returns a YAML document without a file extension in the path
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" && r.URL.Path == "/settings" {
+			w.Header().Set("Content-Type", "application/x-yaml; charset=utf-8")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write(content)
+			return
+		}
+
+		// Else return bad request
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("bad request"))
+	}))
+	defer server.Close()
+
+	// Load configuration from web
+	settings, err := configreader.New[testhelpers.TestSettings]().
+		WithLoader(loader.NewHttp().WithHost(server.Listener.Addr().String()).WithPath("/settings")).
+		Load(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	// Check if settings are the expected
+	if !reflect.DeepEqual(settings, &testhelpers.GoodSettings) {
+		t.Fatalf("settings mismatch")
+	}
+}
@@ -6,13 +6,21 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"net/http"
 	"net/url"
+	"os"
+	"path"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/cenkalti/backoff/v4"
 	"github.com/mxmauro/configreader/internal/helpers"
 	"github.com/mxmauro/configreader/model"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
 )
 
 // -----------------------------------------------------------------------------
@@ -20,6 +28,9 @@ import (
 const (
 	httpRequestTimeout         = 10 * time.Second
 	httpResponseHeadersTimeout = 5 * time.Second
+
+	httpRetryInitialInterval = 500 * time.Millisecond
+	httpRetryMaxInterval     = 30 * time.Second
 )
 
 // -----------------------------------------------------------------------------
@@ -32,17 +43,36 @@ var (
 
 // Http wraps content to be loaded from a URL
 type Http struct {
-	host string
-	path string
+	host      string
+	path      string
+	watchPath string
 
 	credentials *url.Userinfo
 
+	bearerTokenFile string
+	oauth2Config    *clientcredentials.Config
+	oauth2Mtx       sync.Mutex
+	oauth2Token     *oauth2.Token
+
 	query map[string][]string
 
 	headers map[string]string
 
 	tlsConfig *tls.Config
 
+	requestTimeout         time.Duration
+	responseHeadersTimeout time.Duration
+
+	maxRetries           int
+	maxRetryTime         time.Duration
+	retryInitialInterval time.Duration
+	retryMaxInterval     time.Duration
+
+	mtx          sync.Mutex
+	etag         string
+	lastModified string
+	lastValues   model.Values
+
 	err error
 }
 
@@ -64,8 +94,12 @@ func init() {
 // NewHttp create a new web loader
 func NewHttp() *Http {
 	return &Http{
-		query:   make(map[string][]string),
-		headers: make(map[string]string),
+		query:                  make(map[string][]string),
+		headers:                make(map[string]string),
+		requestTimeout:         httpRequestTimeout,
+		responseHeadersTimeout: httpResponseHeadersTimeout,
+		retryInitialInterval:   httpRetryInitialInterval,
+		retryMaxInterval:       httpRetryMaxInterval,
 	}
 }
 
@@ -91,6 +125,17 @@ func (l *Http) WithPath(path string) *Http {
 	return l
 }
 
+// WithWatchPath sets the path of a Server-Sent Events resource that notifies when the content changes
+func (l *Http) WithWatchPath(path string) *Http {
+	if l.err == nil {
+		path, l.err = helpers.ExpandEnvVars(path)
+		if l.err == nil {
+			l.watchPath = path
+		}
+	}
+	return l
+}
+
 // WithCredentials sets the username and password
 func (l *Http) WithCredentials(username string, password string) *Http {
 	if l.err == nil {
@@ -113,6 +158,50 @@ func (l *Http) WithCredentials(username string, password string) *Http {
 	return l
 }
 
+// WithBearerTokenFile sets the name of a file containing a bearer token to send in the Authorization header. The file
+// is read on each load, so rotated tokens, like projected service account ones, are picked up.
+func (l *Http) WithBearerTokenFile(filename string) *Http {
+	if l.err == nil {
+		filename, l.err = helpers.ExpandEnvVars(filename)
+		if l.err == nil {
+			l.bearerTokenFile = filename
+			l.oauth2Config = nil
+		}
+	}
+	return l
+}
+
+// WithOAuth2ClientCredentials sets up the OAuth2 client credentials flow to obtain a bearer token to send in the
+// Authorization header. The token is requested with the same TLS configuration and timeouts as the settings, cached and
+// requested again when it expires.
+func (l *Http) WithOAuth2ClientCredentials(tokenURL string, clientID string, clientSecret string, scopes ...string) *Http {
+	if l.err == nil {
+		var err error
+
+		cfg := clientcredentials.Config{
+			Scopes: scopes,
+		}
+		cfg.TokenURL, err = helpers.ExpandEnvVars(tokenURL)
+		if err == nil {
+			cfg.ClientID, err = helpers.ExpandEnvVars(clientID)
+		}
+		if err == nil {
+			cfg.ClientSecret, err = helpers.ExpandEnvVars(clientSecret)
+		}
+		if err == nil && len(cfg.TokenURL) == 0 {
+			err = errors.New("invalid token url")
+		}
+		if err == nil {
+			l.oauth2Config = &cfg
+			l.oauth2Token = nil
+			l.bearerTokenFile = ""
+		} else {
+			l.err = err
+		}
+	}
+	return l
+}
+
 // WithQuery sets the query parameters
 func (l *Http) WithQuery(query map[string][]string) *Http {
 	if l.err == nil {
@@ -247,6 +336,69 @@ func (l *Http) WithTLS(tlsConfig *tls.Config) *Http {
 	return l
 }
 
+// WithTimeout sets the maximum time a single request can take
+func (l *Http) WithTimeout(timeout time.Duration) *Http {
+	if l.err == nil {
+		if timeout > 0 {
+			l.requestTimeout = timeout
+		} else {
+			l.err = errors.New("invalid timeout")
+		}
+	}
+	return l
+}
+
+// WithResponseHeaderTimeout sets the maximum time to wait for the response headers after sending the request
+func (l *Http) WithResponseHeaderTimeout(timeout time.Duration) *Http {
+	if l.err == nil {
+		if timeout > 0 {
+			l.responseHeadersTimeout = timeout
+		} else {
+			l.err = errors.New("invalid response header timeout")
+		}
+	}
+	return l
+}
+
+// WithRetries sets the number of times a failed request is retried. Only connection errors, timeouts and the 429 and
+// 5xx status codes are retried.
+func (l *Http) WithRetries(maxRetries int) *Http {
+	if l.err == nil {
+		if maxRetries >= 0 {
+			l.maxRetries = maxRetries
+		} else {
+			l.err = errors.New("invalid number of retries")
+		}
+	}
+	return l
+}
+
+// WithMaxRetryTime sets the maximum time to spend retrying a failed request. Zero, the default, means no limit.
+func (l *Http) WithMaxRetryTime(maxRetryTime time.Duration) *Http {
+	if l.err == nil {
+		if maxRetryTime >= 0 {
+			l.maxRetryTime = maxRetryTime
+		} else {
+			l.err = errors.New("invalid maximum retry time")
+		}
+	}
+	return l
+}
+
+// WithRetryInterval sets the initial and the maximum wait time between retries. The wait time grows exponentially,
+// with some jitter, between both values.
+func (l *Http) WithRetryInterval(initialInterval time.Duration, maxInterval time.Duration) *Http {
+	if l.err == nil {
+		if initialInterval > 0 && maxInterval >= initialInterval {
+			l.retryInitialInterval = initialInterval
+			l.retryMaxInterval = maxInterval
+		} else {
+			l.err = errors.New("invalid retry interval")
+		}
+	}
+	return l
+}
+
 // WithURL sets the host, port, path and other settings from the provided url
 func (l *Http) WithURL(rawURL string) *Http {
 	if l.err == nil {
@@ -285,13 +437,208 @@ func (l *Http) WithURL(rawURL string) *Http {
 
 // Load loads the content from the web
 func (l *Http) Load(ctx context.Context) (model.Values, error) {
-	var resp *http.Response
+	var retryBackoff *backoff.ExponentialBackOff
 
 	// If an error was set by a With... function, return it
 	if l.err != nil {
 		return nil, l.err
 	}
 
+	startTime := time.Now()
+	for attempt := 0; ; attempt++ {
+		values, retryAfter, retryable, err := l.loadOnce(ctx)
+		if err == nil {
+			return values, nil
+		}
+		if !retryable || attempt >= l.maxRetries {
+			return nil, err
+		}
+
+		// Calculate the time to wait before retrying
+		if retryBackoff == nil {
+			retryBackoff = &backoff.ExponentialBackOff{
+				InitialInterval:     l.retryInitialInterval,
+				RandomizationFactor: backoff.DefaultRandomizationFactor,
+				Multiplier:          backoff.DefaultMultiplier,
+				MaxInterval:         l.retryMaxInterval,
+				MaxElapsedTime:      l.maxRetryTime,
+				Stop:                backoff.Stop,
+				Clock:               backoff.SystemClock,
+			}
+			retryBackoff.Reset()
+		}
+		toSleep := retryBackoff.NextBackOff()
+		if toSleep == backoff.Stop {
+			return nil, err
+		}
+
+		// Honor the time requested by the server, if any, unless it exceeds the maximum retry time
+		if retryAfter > toSleep {
+			toSleep = retryAfter
+		}
+		if l.maxRetryTime > 0 && time.Since(startTime)+toSleep > l.maxRetryTime {
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(toSleep):
+		}
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// loadOnce executes a single request and returns, on failure, if it can be retried and the time the server asked to
+// wait before doing it
+func (l *Http) loadOnce(ctx context.Context) (model.Values, time.Duration, bool, error) {
+	var resp *http.Response
+
+	// Create a new request
+	req, err := l.newRequest(ctx, l.path)
+	if err != nil {
+		if isConnectionError(ctx, err) || isTimeoutError(ctx, err) {
+			return nil, 0, true, newSourceUnavailableError(err)
+		}
+		return nil, 0, isRetryableCredentialsError(ctx, err), err
+	}
+
+	// Ask the server to send the content only if it changed since the last load
+	l.mtx.Lock()
+	if l.lastValues != nil {
+		if len(l.etag) > 0 {
+			req.Header.Set("If-None-Match", l.etag)
+		}
+		if len(l.lastModified) > 0 {
+			req.Header.Set("If-Modified-Since", l.lastModified)
+		}
+	}
+	l.mtx.Unlock()
+
+	// Execute request
+	ctxWithTimeout, ctxCancel := context.WithTimeout(ctx, l.requestTimeout)
+	defer ctxCancel()
+
+	resp, err = l.newClient().Do(req.WithContext(ctxWithTimeout))
+	if resp != nil && resp.Body != nil {
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+	}
+	if err != nil {
+		if isConnectionError(ctx, err) || isTimeoutError(ctx, err) {
+			return nil, 0, true, newSourceUnavailableError(err)
+		}
+		return nil, 0, false, err
+	}
+
+	// If the content did not change, return the values of the last load
+	if resp.StatusCode == http.StatusNotModified {
+		values := l.getLastValues()
+		if values == nil {
+			return nil, 0, false, fmt.Errorf("unexpected HTTP status code [http-status=%v]", resp.Status)
+		}
+		return values, 0, false, nil
+	}
+
+	// Check if the request succeeded
+	if resp.StatusCode != 200 {
+		err = fmt.Errorf("unexpected HTTP status code [http-status=%v]", resp.Status)
+		retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+			// The OAuth2 token may have been revoked, so request a new one on the next load
+			l.clearOAuth2Token()
+		}
+		retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
+		switch resp.StatusCode {
+		case http.StatusNotFound:
+			return nil, 0, false, newSourceNotFoundError(err)
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return nil, retryAfter, true, newSourceUnavailableError(err)
+		}
+		return nil, retryAfter, retryable, err
+	}
+
+	// Read response body
+	var responseBody []byte
+	responseBody, err = io.ReadAll(resp.Body)
+	if err != nil {
+		if isConnectionError(ctx, err) || isTimeoutError(ctx, err) {
+			return nil, 0, true, newSourceUnavailableError(err)
+		}
+		return nil, 0, false, err
+	}
+
+	// Parse data using the format specified by the content type or, else, the path extension
+	f := findFormatByContentType(resp.Header.Get("Content-Type"))
+	if f == nil {
+		f = findFormatByExtension(path.Ext(req.URL.Path))
+	}
+	values, err := parseData(responseBody, f)
+	if err != nil {
+		return nil, 0, false, err
+	}
+
+	// Remember the values if the server supports conditional requests
+	l.setLastValues(resp.Header.Get("ETag"), resp.Header.Get("Last-Modified"), values)
+
+	// Done
+	return values, 0, false, nil
+}
+
+// -----------------------------------------------------------------------------
+
+// setLastValues stores the values along with the validators the server sent, if any, to use them in the next load
+func (l *Http) setLastValues(etag string, lastModified string, values model.Values) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	l.etag = etag
+	l.lastModified = lastModified
+	if len(etag) > 0 || len(lastModified) > 0 {
+		l.lastValues = values
+	} else {
+		l.lastValues = nil
+	}
+}
+
+// getLastValues returns a copy of the values of the last load or nil if none were stored
+func (l *Http) getLastValues() model.Values {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if l.lastValues == nil {
+		return nil
+	}
+	values := make(model.Values, len(l.lastValues))
+	for k, v := range l.lastValues {
+		values[k] = v
+	}
+	return values
+}
+
+// newClient creates the http client object and sets up the transport
+func (l *Http) newClient() *http.Client {
+	client := http.Client{
+		Transport: httpTransport,
+	}
+	if l.tlsConfig != nil || (l.responseHeadersTimeout > 0 && l.responseHeadersTimeout != httpResponseHeadersTimeout) {
+		// Clone our default transport
+		t := httpTransport.Clone()
+		t.TLSClientConfig = l.tlsConfig
+		if l.responseHeadersTimeout > 0 {
+			t.ResponseHeaderTimeout = l.responseHeadersTimeout
+		}
+
+		client.Transport = t
+	}
+	return &client
+}
+
+// newRequest creates a request for the given resource path with the configured credentials, query parameters and
+// headers
+func (l *Http) newRequest(ctx context.Context, resourcePath string) (*http.Request, error) {
 	// Start building URL
 	u := &url.URL{
 		Scheme: "http",
@@ -307,10 +654,10 @@ func (l *Http) Load(ctx context.Context) (model.Values, error) {
 	u.Host = l.host
 
 	// Set path
-	if len(l.path) == 0 {
+	if len(resourcePath) == 0 {
 		return nil, errors.New("path not set")
 	}
-	u.Path = l.path
+	u.Path = resourcePath
 
 	// Set credentials
 	u.User = l.credentials
@@ -326,18 +673,6 @@ func (l *Http) Load(ctx context.Context) (model.Values, error) {
 		u.RawQuery = query.Encode()
 	}
 
-	// Create the http client object and set up transport
-	client := http.Client{
-		Transport: httpTransport,
-	}
-	if l.tlsConfig != nil {
-		// Clone our default transport
-		t := httpTransport.Clone()
-		t.TLSClientConfig = l.tlsConfig
-
-		client.Transport = t
-	}
-
 	// Create a new request
 	req, err := http.NewRequest("GET", u.String(), nil)
 	if err != nil {
@@ -351,35 +686,85 @@ func (l *Http) Load(ctx context.Context) (model.Values, error) {
 		}
 	}
 
-	// Execute request
-	ctxWithTimeout, ctxCancel := context.WithTimeout(ctx, httpRequestTimeout)
-	defer ctxCancel()
-
-	resp, err = client.Do(req.WithContext(ctxWithTimeout))
-	if resp != nil && resp.Body != nil {
-		defer func() {
-			_ = resp.Body.Close()
-		}()
+	// Set the bearer token if provided. It takes precedence over the username and password.
+	if len(l.bearerTokenFile) > 0 {
+		token, err := os.ReadFile(l.bearerTokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read bearer token file [err=%w]", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	} else if l.oauth2Config != nil {
+		token, err := l.getOAuth2Token(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get OAuth2 token [err=%w]", err)
+		}
+		token.SetAuthHeader(req)
 	}
+
+	// Done
+	return req, nil
+}
+
+// getOAuth2Token returns the cached OAuth2 token or, if it expired, requests a new one using the same client settings,
+// like the TLS configuration and timeouts, as the loader requests
+func (l *Http) getOAuth2Token(ctx context.Context) (*oauth2.Token, error) {
+	l.oauth2Mtx.Lock()
+	defer l.oauth2Mtx.Unlock()
+
+	client := l.newClient()
+	client.Timeout = l.requestTimeout
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, client)
+
+	token, err := oauth2.ReuseTokenSource(l.oauth2Token, l.oauth2Config.TokenSource(ctx)).Token()
 	if err != nil {
 		return nil, err
 	}
+	l.oauth2Token = token
 
-	// Check if the request succeeded
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("unexpected HTTP status code [http-status=%v]", resp.Status)
-	}
+	// Done
+	return token, nil
+}
 
-	// Read response body
-	var responseBody []byte
-	responseBody, err = io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
+// clearOAuth2Token discards the cached OAuth2 token, if any
+func (l *Http) clearOAuth2Token() {
+	l.oauth2Mtx.Lock()
+	defer l.oauth2Mtx.Unlock()
+
+	l.oauth2Token = nil
+}
+
+// isRetryableCredentialsError returns true if getting the credentials failed for a reason that may be transient, like
+// a token endpoint replying with a 429 or 5xx status code or a token file that cannot be read while being replaced
+func isRetryableCredentialsError(ctx context.Context, err error) bool {
+	var retrieveErr *oauth2.RetrieveError
+	var pathErr *fs.PathError
+
+	// Ignore errors caused by the caller's context
+	if ctx.Err() != nil {
+		return false
 	}
+	if errors.As(err, &retrieveErr) {
+		return retrieveErr.Response != nil &&
+			(retrieveErr.Response.StatusCode == http.StatusTooManyRequests || retrieveErr.Response.StatusCode >= 500)
+	}
+	return errors.As(err, &pathErr)
+}
 
-	// Parse data
-	if strings.HasSuffix(u.Path, ".env") {
-		return parseData(responseBody, parseDataHintIsDotEnv)
+// parseRetryAfter returns the time to wait specified in a Retry-After header, either in seconds or as a date
+func parseRetryAfter(value string) time.Duration {
+	if len(value) == 0 {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+		return 0
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
 	}
-	return parseData(responseBody, 0)
+	return 0
 }
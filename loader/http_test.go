@@ -2,11 +2,19 @@ package loader_test
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"reflect"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/mxmauro/configreader"
 	"github.com/mxmauro/configreader/internal/testhelpers"
@@ -51,3 +59,431 @@ func TestHttpLoader(t *testing.T) {
 		t.Fatalf("settings mismatch")
 	}
 }
+
+func TestHttpLoaderConditionalRequests(t *testing.T) {
+	var fullResponses int32
+	var notModifiedResponses int32
+
+	const etag = `"v1"`
+
+	// Create a test http server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/settings" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte("bad request"))
+			return
+		}
+
+		if r.Header.Get("If-None-Match") == etag {
+			atomic.AddInt32(&notModifiedResponses, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		atomic.AddInt32(&fullResponses, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", etag)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{ "HOST": "localhost", "PORT": 80 }`))
+	}))
+	defer server.Close()
+
+	l := loader.NewHttp().WithHost(server.Listener.Addr().String()).WithPath("/settings")
+
+	for i := 0; i < 3; i++ {
+		values, err := l.Load(context.Background())
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+		if values["HOST"] != "localhost" {
+			t.Fatalf("unexpected values %+v", values)
+		}
+	}
+
+	// Check the content was only downloaded once
+	if atomic.LoadInt32(&fullResponses) != 1 || atomic.LoadInt32(&notModifiedResponses) != 2 {
+		t.Fatalf("unexpected responses [full=%d] [not-modified=%d]", fullResponses, notModifiedResponses)
+	}
+}
+
+func TestHttpLoaderRetries(t *testing.T) {
+	var requests int32
+
+	// Create a test http server that fails several times before succeeding
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/flaky":
+			switch atomic.AddInt32(&requests, 1) {
+			case 1:
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			case 2:
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{ "HOST": "localhost" }`))
+			return
+
+		case "/slow":
+			atomic.AddInt32(&requests, 1)
+			select {
+			case <-r.Context().Done():
+			case <-time.After(2 * time.Second):
+			}
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("bad request"))
+	}))
+	defer server.Close()
+
+	newLoader := func(path string) *loader.Http {
+		return loader.NewHttp().
+			WithHost(server.Listener.Addr().String()).
+			WithPath(path).
+			WithRetries(3).
+			WithRetryInterval(10*time.Millisecond, 50*time.Millisecond)
+	}
+
+	// Server errors must be retried honoring the Retry-After header
+	start := time.Now()
+	values, err := newLoader("/flaky").Load(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if values["HOST"] != "localhost" || atomic.LoadInt32(&requests) != 3 {
+		t.Fatalf("unexpected result [values=%+v] [requests=%d]", values, requests)
+	}
+	if time.Since(start) < time.Second {
+		t.Fatalf("Retry-After header not honored")
+	}
+
+	// Client errors must not be retried
+	atomic.StoreInt32(&requests, 0)
+	_, err = newLoader("/bad").Load(context.Background())
+	if err == nil || atomic.LoadInt32(&requests) != 1 {
+		t.Fatalf("unexpected result [err=%v] [requests=%d]", err, requests)
+	}
+
+	// Timeouts must be retried and reported as an unavailable source
+	atomic.StoreInt32(&requests, 0)
+	_, err = newLoader("/slow").WithTimeout(100 * time.Millisecond).WithRetries(1).Load(context.Background())
+	if !errors.Is(err, loader.ErrSourceUnavailable) || atomic.LoadInt32(&requests) != 2 {
+		t.Fatalf("unexpected result [err=%v] [requests=%d]", err, requests)
+	}
+}
+
+func TestHttpLoaderBearerTokenFile(t *testing.T) {
+	// Create a test http server that expects the token stored in the file
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/settings" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{ "AUTHORIZATION": "` + r.Header.Get("Authorization") + `" }`))
+	}))
+	defer server.Close()
+
+	tokenFilename := filepath.Join(t.TempDir(), "token")
+
+	l := loader.NewHttp().
+		WithHost(server.Listener.Addr().String()).
+		WithPath("/settings").
+		WithBearerTokenFile(tokenFilename)
+
+	// The token must be read on each load
+	for _, token := range []string{"token1", "token2"} {
+		err := os.WriteFile(tokenFilename, []byte(token+"\n"), 0600)
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+
+		values, err := l.Load(context.Background())
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+		if values["AUTHORIZATION"] != "Bearer "+token {
+			t.Fatalf("unexpected authorization header %v", values["AUTHORIZATION"])
+		}
+	}
+}
+
+func TestHttpLoaderOAuth2ClientCredentials(t *testing.T) {
+	var tokenRequests int32
+
+	// Create a test http server acting as both the token endpoint and the config server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			clientID, clientSecret, _ := r.BasicAuth()
+			if r.Method != "POST" || r.FormValue("grant_type") != "client_credentials" || r.FormValue("scope") != "config.read" ||
+				clientID != "client" || clientSecret != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			atomic.AddInt32(&tokenRequests, 1)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{ "access_token": "access-token", "token_type": "Bearer", "expires_in": 3600 }`))
+			return
+
+		case "/settings":
+			if r.Header.Get("Authorization") != "Bearer access-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{ "HOST": "localhost" }`))
+			return
+		}
+
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	l := loader.NewHttp().
+		WithHost(server.Listener.Addr().String()).
+		WithPath("/settings").
+		WithOAuth2ClientCredentials(server.URL+"/token", "client", "secret", "config.read")
+
+	for i := 0; i < 2; i++ {
+		values, err := l.Load(context.Background())
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+		if values["HOST"] != "localhost" {
+			t.Fatalf("unexpected values %+v", values)
+		}
+	}
+
+	// The token must be cached
+	if atomic.LoadInt32(&tokenRequests) != 1 {
+		t.Fatalf("unexpected number of token requests %d", tokenRequests)
+	}
+}
+
+func TestHttpLoaderOAuth2ClientCredentialsTLS(t *testing.T) {
+	// Create a test https server acting as both the token endpoint and the config server
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{ "access_token": "access-token", "token_type": "Bearer", "expires_in": 3600 }`))
+			return
+
+		case "/settings":
+			if r.Header.Get("Authorization") != "Bearer access-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{ "HOST": "localhost" }`))
+			return
+		}
+
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	// The server certificate is only trusted through the loader TLS configuration, so it must also be used to request
+	// the token
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(server.Certificate())
+
+	values, err := loader.NewHttp().
+		WithHost(server.Listener.Addr().String()).
+		WithPath("/settings").
+		WithTLS(&tls.Config{
+			RootCAs: rootCAs,
+		}).
+		WithOAuth2ClientCredentials(server.URL+"/token", "client", "secret").
+		Load(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if values["HOST"] != "localhost" {
+		t.Fatalf("unexpected values %+v", values)
+	}
+}
+
+func TestHttpLoaderOAuth2ClientCredentialsTimeout(t *testing.T) {
+	doneCh := make(chan struct{})
+
+	// Create a test http server with a token endpoint that does not reply until the test ends
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-doneCh
+	}))
+	defer server.Close()
+	defer close(doneCh)
+
+	l := loader.NewHttp().
+		WithHost(server.Listener.Addr().String()).
+		WithPath("/settings").
+		WithTimeout(200*time.Millisecond).
+		WithOAuth2ClientCredentials(server.URL+"/token", "client", "secret")
+
+	// The token request must honor the loader timeout
+	start := time.Now()
+	_, err := l.Load(context.Background())
+	if err == nil {
+		t.Fatalf("load succeeded")
+	}
+	if time.Since(start) > 3*time.Second {
+		t.Fatalf("token request did not honor the loader timeout")
+	}
+
+	// And the load context
+	ctx, cancelCtx := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancelCtx()
+
+	start = time.Now()
+	_, err = l.WithTimeout(time.Minute).Load(ctx)
+	if err == nil {
+		t.Fatalf("load succeeded")
+	}
+	if time.Since(start) > 3*time.Second {
+		t.Fatalf("token request did not honor the load context")
+	}
+}
+
+func TestHttpLoaderOAuth2ClientCredentialsRevoked(t *testing.T) {
+	var tokenRequests int32
+	var validToken atomic.Value
+	validToken.Store("access-token-1")
+
+	// Create a test http server that only accepts the last issued token
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			token := fmt.Sprintf("access-token-%d", atomic.AddInt32(&tokenRequests, 1))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{ "access_token": "` + token + `", "token_type": "Bearer", "expires_in": 3600 }`))
+			return
+
+		case "/settings":
+			if r.Header.Get("Authorization") != "Bearer "+validToken.Load().(string) {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{ "HOST": "localhost" }`))
+			return
+		}
+
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	l := loader.NewHttp().
+		WithHost(server.Listener.Addr().String()).
+		WithPath("/settings").
+		WithOAuth2ClientCredentials(server.URL+"/token", "client", "secret")
+
+	_, err := l.Load(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	// Revoke the token. The next load fails but the cached token must be discarded.
+	validToken.Store("access-token-2")
+
+	_, err = l.Load(context.Background())
+	if err == nil {
+		t.Fatalf("load succeeded")
+	}
+
+	values, err := l.Load(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if values["HOST"] != "localhost" || atomic.LoadInt32(&tokenRequests) != 2 {
+		t.Fatalf("unexpected result [values=%+v] [token-requests=%d]", values, tokenRequests)
+	}
+}
+
+func TestHttpLoaderCredentialsRetries(t *testing.T) {
+	var tokenRequests int32
+
+	// Create a test http server with a token endpoint that fails the first two times. The OAuth2 client sends a second
+	// request if the first one fails, trying to find out how the credentials must be sent.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			if atomic.AddInt32(&tokenRequests, 1) <= 2 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{ "access_token": "access-token", "token_type": "Bearer", "expires_in": 3600 }`))
+			return
+
+		case "/settings":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{ "AUTHORIZATION": "` + r.Header.Get("Authorization") + `" }`))
+			return
+		}
+
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	newLoader := func() *loader.Http {
+		return loader.NewHttp().
+			WithHost(server.Listener.Addr().String()).
+			WithPath("/settings").
+			WithRetries(3).
+			WithRetryInterval(100*time.Millisecond, 100*time.Millisecond)
+	}
+
+	// Token endpoint errors must be retried
+	values, err := newLoader().WithOAuth2ClientCredentials(server.URL+"/token", "client", "secret").Load(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if values["AUTHORIZATION"] != "Bearer access-token" || atomic.LoadInt32(&tokenRequests) != 3 {
+		t.Fatalf("unexpected result [values=%+v] [token-requests=%d]", values, tokenRequests)
+	}
+
+	// An unreachable token endpoint must be reported as an unavailable source
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	closedAddr := listener.Addr().String()
+	_ = listener.Close()
+
+	_, err = newLoader().WithOAuth2ClientCredentials("http://"+closedAddr+"/token", "client", "secret").Load(context.Background())
+	if !errors.Is(err, loader.ErrSourceUnavailable) {
+		t.Fatalf("unexpected result [err=%v]", err)
+	}
+
+	// A token file that cannot be read yet must be retried
+	tokenFilename := filepath.Join(t.TempDir(), "token")
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = os.WriteFile(tokenFilename, []byte("token"), 0600)
+	}()
+
+	values, err = newLoader().WithRetryInterval(time.Second, time.Second).WithBearerTokenFile(tokenFilename).Load(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if values["AUTHORIZATION"] != "Bearer token" {
+		t.Fatalf("unexpected authorization header %v", values["AUTHORIZATION"])
+	}
+}
@@ -0,0 +1,125 @@
+package loader
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// -----------------------------------------------------------------------------
+
+const (
+	httpWatchReconnectDelay       = 2 * time.Second
+	httpWatchMaxReconnectAttempts = 5
+)
+
+// -----------------------------------------------------------------------------
+
+// Watch returns a channel that receives a signal each time the Server-Sent Events resource set with WithWatchPath
+// sends an event. The channel is closed when the context is done. If no watch path was set, nil is returned.
+//
+// The connection is re-established if lost and, because events may have been missed meanwhile, a signal is also sent
+// after reconnecting. If the initial connection fails or the connection cannot be re-established after several
+// attempts, the channel is closed so a monitor falls back to polling.
+func (l *Http) Watch(ctx context.Context) <-chan struct{} {
+	if l.err != nil || len(l.watchPath) == 0 {
+		return nil
+	}
+
+	ch := make(chan struct{}, 1)
+
+	go func() {
+		defer close(ch)
+
+		reconnecting := false
+		failedAttempts := 0
+		for {
+			connected, _ := l.watchEvents(ctx, ch, reconnecting)
+			if connected {
+				failedAttempts = 0
+			} else {
+				// Give up if the resource cannot be watched
+				if !reconnecting || failedAttempts >= httpWatchMaxReconnectAttempts {
+					return
+				}
+				failedAttempts += 1
+			}
+
+			// Wait before reconnecting
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(httpWatchReconnectDelay):
+			}
+			reconnecting = true
+		}
+	}()
+
+	// Done
+	return ch
+}
+
+// -----------------------------------------------------------------------------
+
+// watchEvents connects to the watch resource and forwards its events until the connection is closed. It returns true
+// if the connection was established.
+func (l *Http) watchEvents(ctx context.Context, ch chan struct{}, reconnecting bool) (bool, error) {
+	var resp *http.Response
+
+	signal := func() {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+
+	// Create a new request
+	req, err := l.newRequest(ctx, l.watchPath)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Cache-Control", "no-cache")
+
+	// Execute request. The stream is kept open, so only the context limits its duration.
+	resp, err = l.newClient().Do(req.WithContext(ctx))
+	if resp != nil && resp.Body != nil {
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+	}
+	if err != nil {
+		return false, err
+	}
+
+	// Check if the request succeeded
+	if resp.StatusCode != 200 {
+		return false, fmt.Errorf("unexpected HTTP status code [http-status=%v]", resp.Status)
+	}
+
+	if reconnecting {
+		signal()
+	}
+
+	// Read events. Each one ends with an empty line and lines starting with a colon are comments, usually sent to keep
+	// the connection alive.
+	pendingEvent := false
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if len(line) == 0 {
+			if pendingEvent {
+				signal()
+				pendingEvent = false
+			}
+		} else if !strings.HasPrefix(line, ":") {
+			pendingEvent = true
+		}
+	}
+
+	// Done
+	return true, scanner.Err()
+}
@@ -0,0 +1,173 @@
+package loader_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mxmauro/configreader"
+	"github.com/mxmauro/configreader/loader"
+)
+
+// -----------------------------------------------------------------------------
+
+type HttpWatchTest struct {
+	Value int `config:"HTTP_WATCH_TEST_VALUE"`
+}
+
+// -----------------------------------------------------------------------------
+
+func TestMonitorWithHttpWatch(t *testing.T) {
+	var value int32 = 1
+
+	eventCh := make(chan struct{})
+
+	// Create a test http server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/settings":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{ "HTTP_WATCH_TEST_VALUE": ` + string(rune('0'+atomic.LoadInt32(&value))) + ` }`))
+			return
+
+		case "/events":
+			if r.Header.Get("Accept") != "text/event-stream" {
+				break
+			}
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(": keep-alive\n\n"))
+			w.(http.Flusher).Flush()
+
+			for {
+				select {
+				case <-r.Context().Done():
+					return
+				case <-eventCh:
+					_, _ = w.Write([]byte("event: changed\ndata: {}\n\n"))
+					w.(http.Flusher).Flush()
+				}
+			}
+		}
+
+		// Else return bad request
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("bad request"))
+	}))
+	defer server.Close()
+
+	settingsCh := make(chan *HttpWatchTest, 10)
+
+	// Use a long poll interval so only the event stream can trigger a reload in time
+	settingsMonitor := configreader.NewMonitor[HttpWatchTest](time.Hour, func(settings *HttpWatchTest, loadErr error) {
+		if loadErr == nil {
+			settingsCh <- settings
+		}
+	})
+	defer settingsMonitor.Destroy()
+
+	_, err := configreader.New[HttpWatchTest]().
+		WithLoader(loader.NewHttp().
+			WithHost(server.Listener.Addr().String()).
+			WithPath("/settings").
+			WithWatchPath("/events")).
+		WithMonitor(settingsMonitor).
+		Load(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	atomic.StoreInt32(&value, 2)
+
+	// Send the event once the stream is connected
+	select {
+	case eventCh <- struct{}{}:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("event stream not connected")
+	}
+
+	select {
+	case settings := <-settingsCh:
+		if settings.Value != 2 {
+			t.Fatalf("unexpected value %d (should be 2)", settings.Value)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("settings change not received")
+	}
+}
+
+func TestMonitorWithFailingHttpWatch(t *testing.T) {
+	var value int32 = 1
+
+	// Create a test http server without an event stream
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/settings" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{ "HTTP_WATCH_TEST_VALUE": ` + string(rune('0'+atomic.LoadInt32(&value))) + ` }`))
+			return
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("not found"))
+	}))
+	defer server.Close()
+
+	l := loader.NewHttp().
+		WithHost(server.Listener.Addr().String()).
+		WithPath("/settings").
+		WithWatchPath("/events")
+
+	// The watch channel must be closed if the event stream cannot be opened
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	select {
+	case _, ok := <-l.Watch(ctx):
+		if ok {
+			t.Fatalf("unexpected signal")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("watch channel not closed")
+	}
+
+	// And the monitor must fall back to polling
+	settingsCh := make(chan *HttpWatchTest, 10)
+
+	settingsMonitor := configreader.NewMonitor[HttpWatchTest](100*time.Millisecond, func(settings *HttpWatchTest, loadErr error) {
+		if loadErr == nil {
+			settingsCh <- settings
+		}
+	})
+	defer settingsMonitor.Destroy()
+
+	_, err := configreader.New[HttpWatchTest]().
+		WithLoader(l).
+		WithMonitor(settingsMonitor).
+		Load(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	atomic.StoreInt32(&value, 2)
+
+	select {
+	case settings := <-settingsCh:
+		if settings.Value != 2 {
+			t.Fatalf("unexpected value %d (should be 2)", settings.Value)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("settings change not received")
+	}
+}
+
+func TestHttpWatchWithoutWatchPath(t *testing.T) {
+	l := loader.NewHttp().WithHost("127.0.0.1").WithPath("/settings")
+	if l.Watch(context.Background()) != nil {
+		t.Fatalf("loader without watch path must not be watchable")
+	}
+}
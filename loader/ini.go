@@ -0,0 +1,86 @@
+package loader
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/mxmauro/configreader/model"
+)
+
+// -----------------------------------------------------------------------------
+
+func parseINI(data []byte) (model.Values, error) {
+	ret := make(model.Values)
+
+	keyPrefix := ""
+	for lineNo := 1; len(data) > 0; lineNo++ {
+		// Look for the end of line
+		eolIdx := bytes.IndexByte(data, '\n')
+		if eolIdx < 0 {
+			eolIdx = len(data)
+		}
+		line := strings.Trim(string(data[:eolIdx]), " \t")
+
+		// Continue in next line
+		if eolIdx < len(data) {
+			eolIdx += 1
+		}
+		data = data[eolIdx:]
+
+		// A comment or empty line
+		if len(line) == 0 || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+
+		// A section header
+		if line[0] == '[' {
+			endIdx := strings.IndexByte(line, ']')
+			if endIdx < 0 {
+				return nil, fmt.Errorf("invalid ini section header [line=%d]", lineNo)
+			}
+			section := strings.Trim(line[1:endIdx], " \t")
+			if len(section) == 0 {
+				keyPrefix = ""
+			} else {
+				keyPrefix = strings.ToUpper(section) + "_"
+			}
+			continue
+		}
+
+		// Get key name
+		sepIdx := strings.IndexAny(line, "=:")
+		if sepIdx <= 0 {
+			return nil, fmt.Errorf("invalid ini key [line=%d]", lineNo)
+		}
+		key := strings.Trim(line[:sepIdx], " \t")
+		if len(key) == 0 {
+			return nil, fmt.Errorf("invalid ini key [line=%d]", lineNo)
+		}
+		key = keyPrefix + strings.ToUpper(key)
+
+		// Get value
+		ret[key] = parseINIValue(strings.Trim(line[sepIdx+1:], " \t"))
+	}
+
+	// Done
+	return ret, nil
+}
+
+func parseINIValue(value string) string {
+	// Quoted values are taken verbatim
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') {
+		endIdx := strings.IndexByte(value[1:], value[0])
+		if endIdx >= 0 {
+			return value[1 : endIdx+1]
+		}
+	}
+
+	// Strip inline comments
+	for idx := 1; idx < len(value); idx++ {
+		if (value[idx] == ';' || value[idx] == '#') && (value[idx-1] == ' ' || value[idx-1] == '\t') {
+			return strings.TrimRight(value[:idx], " \t")
+		}
+	}
+	return value
+}
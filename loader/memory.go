@@ -32,7 +32,7 @@ func NewMemoryFromEnvironmentVariable(Name string) *Memory {
 	if ok {
 		if len(data) > 0 {
 			// Make a copy of the source data, so we can safely manipulate it
-			l.data, l.err = parseData([]byte(data), 0)
+			l.data, l.err = parseData([]byte(data), nil)
 		} else {
 			l.err = errors.New("environment variable '" + Name + "' is empty")
 		}
@@ -0,0 +1,49 @@
+package loader
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mxmauro/configreader/model"
+)
+
+// -----------------------------------------------------------------------------
+
+// OptionalLoader wraps a loader whose source may be absent or unreachable
+type OptionalLoader struct {
+	l model.Loader
+}
+
+// -----------------------------------------------------------------------------
+
+// Optional creates a loader that returns no values, instead of failing, if the wrapped loader returns an error
+// wrapping ErrSourceNotFound or ErrSourceUnavailable
+func Optional(l model.Loader) *OptionalLoader {
+	return &OptionalLoader{
+		l: l,
+	}
+}
+
+// Load loads the content from the wrapped loader
+func (l *OptionalLoader) Load(ctx context.Context) (model.Values, error) {
+	values, err := l.l.Load(ctx)
+	if err != nil {
+		if errors.Is(err, ErrSourceNotFound) || errors.Is(err, ErrSourceUnavailable) {
+			return model.Values{}, nil
+		}
+		return nil, err
+	}
+
+	// Done
+	return values, nil
+}
+
+// Unwrap returns the wrapped loader
+func (l *OptionalLoader) Unwrap() model.Loader {
+	return l.l
+}
+
+// Watch watches the wrapped loader if it implements model.Watcher, else returns nil
+func (l *OptionalLoader) Watch(ctx context.Context) <-chan struct{} {
+	return watchLoader(ctx, l.l)
+}
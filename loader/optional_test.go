@@ -0,0 +1,146 @@
+package loader_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mxmauro/configreader"
+	"github.com/mxmauro/configreader/loader"
+	"github.com/mxmauro/configreader/model"
+)
+
+// -----------------------------------------------------------------------------
+
+type OptionalLoaderTest struct {
+	Name string `config:"OPTIONAL_TEST_NAME"`
+}
+
+// -----------------------------------------------------------------------------
+
+func TestSourceErrors(t *testing.T) {
+	// Missing file
+	_, err := loader.NewFile().WithFilename(filepath.Join(t.TempDir(), "missing.env")).Load(context.Background())
+	if !errors.Is(err, loader.ErrSourceNotFound) || !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("unexpected error [err=%v]", err)
+	}
+
+	// Missing http resource
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	addr := server.Listener.Addr().String()
+
+	_, err = loader.NewHttp().WithHost(addr).WithPath("/settings").Load(context.Background())
+	if !errors.Is(err, loader.ErrSourceNotFound) {
+		t.Fatalf("unexpected error [err=%v]", err)
+	}
+
+	// Unreachable http server
+	server.Close()
+
+	_, err = loader.NewHttp().WithHost(addr).WithPath("/settings").Load(context.Background())
+	if !errors.Is(err, loader.ErrSourceUnavailable) {
+		t.Fatalf("unexpected error [err=%v]", err)
+	}
+}
+
+func TestSourceErrorsClassification(t *testing.T) {
+	// Closed port
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to create listener [err=%v]", err)
+	}
+	addr := listener.Addr().String()
+	_ = listener.Close()
+
+	_, err = loader.NewHttp().WithHost(addr).WithPath("/settings").Load(context.Background())
+	if !errors.Is(err, loader.ErrSourceUnavailable) {
+		t.Fatalf("unexpected error [err=%v]", err)
+	}
+
+	// Server with an untrusted certificate. It is reachable but misconfigured, so the error must not be ignored.
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	l := loader.NewHttp().WithHost(server.Listener.Addr().String()).WithPath("/settings").WithDefaultTLS()
+	_, err = l.Load(context.Background())
+	if err == nil || errors.Is(err, loader.ErrSourceUnavailable) || errors.Is(err, loader.ErrSourceNotFound) {
+		t.Fatalf("unexpected error [err=%v]", err)
+	}
+
+	_, err = configreader.New[OptionalLoaderTest]().
+		WithLoader(loader.Optional(l)).
+		Load(context.Background())
+	if err == nil {
+		t.Fatalf("error not returned")
+	}
+}
+
+func TestOptionalLoader(t *testing.T) {
+	settings, err := configreader.New[OptionalLoaderTest]().
+		WithLoader(loader.NewMemory().WithData(model.Values{
+			"OPTIONAL_TEST_NAME": "base",
+		})).
+		WithLoader(loader.Optional(loader.NewFile().WithFilename(filepath.Join(t.TempDir(), "override.env")))).
+		Load(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if settings.Name != "base" {
+		t.Fatalf("unexpected settings [name=%v]", settings.Name)
+	}
+
+	// Other errors must not be ignored
+	_, err = configreader.New[OptionalLoaderTest]().
+		WithLoader(loader.Optional(loader.NewCallback().WithCallback(func(_ context.Context) (model.Values, error) {
+			return nil, errors.New("broken source")
+		}))).
+		Load(context.Background())
+	if err == nil {
+		t.Fatalf("error not returned")
+	}
+}
+
+func TestFirstOfLoader(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "settings.env")
+	err := os.WriteFile(filename, []byte("OPTIONAL_TEST_NAME=file\n"), 0600)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	settings, report, err := configreader.New[OptionalLoaderTest]().
+		WithLoader(loader.FirstOf(
+			loader.NewFile().WithFilename(filepath.Join(t.TempDir(), "missing.env")),
+			loader.NewFile().WithFilename(filename),
+			loader.NewMemory().WithData(model.Values{
+				"OPTIONAL_TEST_NAME": "memory",
+			}),
+		)).
+		LoadWithReport(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if settings.Name != "file" {
+		t.Fatalf("unexpected settings [name=%v]", settings.Name)
+	}
+	if report.Fields["Name"].Source != "File" {
+		t.Fatalf("unexpected source [source=%v]", report.Fields["Name"].Source)
+	}
+
+	// All loaders failing
+	_, err = loader.FirstOf(
+		loader.NewFile().WithFilename(filepath.Join(t.TempDir(), "missing1.env")),
+		loader.NewFile().WithFilename(filepath.Join(t.TempDir(), "missing2.env")),
+	).Load(context.Background())
+	if !errors.Is(err, loader.ErrSourceNotFound) {
+		t.Fatalf("unexpected error [err=%v]", err)
+	}
+}
@@ -0,0 +1,25 @@
+package loader
+
+import (
+	"github.com/BurntSushi/toml"
+	"github.com/mxmauro/configreader/model"
+)
+
+// -----------------------------------------------------------------------------
+
+func parseTOML(data []byte) (model.Values, error) {
+	raw := make(map[string]interface{})
+
+	err := toml.Unmarshal(data, &raw)
+	if err != nil {
+		return nil, err
+	}
+
+	// Normalize decoded values, so they look like the ones decoded from a JSON object
+	for k, v := range raw {
+		raw[k] = normalizeDecodedValue(v)
+	}
+
+	// Done
+	return raw, nil
+}
@@ -9,6 +9,7 @@ import (
 	"os"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/vault/api"
@@ -32,6 +33,11 @@ type Vault struct {
 
 	client *vaultClient
 
+	leaseMtx       sync.Mutex
+	leased         bool
+	leaseRenewAt   time.Time
+	leaseUpdatedCh chan struct{}
+
 	err error
 }
 
@@ -398,12 +404,27 @@ func (l *Vault) Load(ctx context.Context) (model.Values, error) {
 
 	// Read secrets
 	ret = make(model.Values)
+	allLeased := true
+	var leaseTTL time.Duration
 	for _, p := range l.path {
 		secret, err = l.client.readWithContext(ctx, p)
 		if err != nil {
+			if isConnectionError(ctx, err) {
+				return nil, newSourceUnavailableError(err)
+			}
 			return nil, err
 		}
 
+		// Keep track of the secret that expires first
+		if secret != nil && len(secret.LeaseID) > 0 && secret.LeaseDuration > 0 {
+			ttl := time.Duration(secret.LeaseDuration) * time.Second
+			if leaseTTL == 0 || ttl < leaseTTL {
+				leaseTTL = ttl
+			}
+		} else {
+			allLeased = false
+		}
+
 		// If we don't have a secret but also no errors, skip
 		if secret != nil {
 			// Extract data
@@ -424,6 +445,8 @@ func (l *Vault) Load(ctx context.Context) (model.Values, error) {
 		}
 	}
 
+	l.setLease(allLeased, leaseTTL)
+
 	// Done
 	return ret, nil
 }
@@ -61,8 +61,8 @@ func (a *VaultAwsAuth) WithRole(role string) *VaultAwsAuth {
 func (a *VaultAwsAuth) WithType(_type interface{}) *VaultAwsAuth {
 	if a.err == nil {
 		i, err := helpers.GetEnumEnv(_type, []helpers.EnumEnvAllowedValues{
-			{"iam", VaultAwsAuthTypeIAM},
-			{"ec2", VaultAwsAuthTypeEC2},
+			{Name: "iam", Value: VaultAwsAuthTypeIAM},
+			{Name: "ec2", Value: VaultAwsAuthTypeEC2},
 		})
 		if err == nil {
 			a._type = i
@@ -106,9 +106,9 @@ func (a *VaultAwsAuth) WithIamServerID(id string) *VaultAwsAuth {
 func (a *VaultAwsAuth) WithSignature(signature interface{}) *VaultAwsAuth {
 	if a.err == nil {
 		i, err := helpers.GetEnumEnv(signature, []helpers.EnumEnvAllowedValues{
-			{"identity", VaultAwsAuthSignatureIdentity},
-			{"pkcs7", VaultAwsAuthSignaturePKCS7},
-			{"rsa2048", VaultAwsAuthSignatureRSA2048},
+			{Name: "identity", Value: VaultAwsAuthSignatureIdentity},
+			{Name: "pkcs7", Value: VaultAwsAuthSignaturePKCS7},
+			{Name: "rsa2048", Value: VaultAwsAuthSignatureRSA2048},
 		})
 		if err == nil {
 			a.signature = i
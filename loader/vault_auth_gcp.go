@@ -56,8 +56,8 @@ func (a *VaultGcpAuth) WithRole(role string) *VaultGcpAuth {
 func (a *VaultGcpAuth) WithType(_type interface{}) *VaultGcpAuth {
 	if a.err == nil {
 		i, err := helpers.GetEnumEnv(_type, []helpers.EnumEnvAllowedValues{
-			{"gce", VaultGcpAuthTypeGCE},
-			{"iam", VaultGcpAuthTypeIAM},
+			{Name: "gce", Value: VaultGcpAuthTypeGCE},
+			{Name: "iam", Value: VaultGcpAuthTypeIAM},
 		})
 		if err == nil {
 			a._type = i
@@ -89,9 +89,11 @@ func newVaultClient(host string, headers map[string]string, tlsConfig *tls.Confi
 			messageCh: make(chan tokenMonitorMessage, 2),
 		},
 	}
-	client.auth, err = auth.create()
-	if err != nil {
-		return nil, err
+	if len(accessToken) == 0 {
+		client.auth, err = auth.create()
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// Setup Vault api client configuration
@@ -0,0 +1,48 @@
+package loader_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mxmauro/configreader/loader"
+)
+
+// -----------------------------------------------------------------------------
+
+func TestVaultLoaderWithAccessToken(t *testing.T) {
+	// Create a fake Vault server that only accepts the token
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" && r.URL.Path == "/v1/secret/data/app" && r.Header.Get("X-Vault-Token") == "test-token" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"data": map[string]interface{}{
+						"HOST": "example.com",
+					},
+				},
+			})
+			return
+		}
+
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{ "errors": [ "permission denied" ] }`))
+	}))
+	defer server.Close()
+
+	// Load using the token only, without an authorization method
+	values, err := loader.NewVault().
+		WithHost(server.Listener.Addr().String()).
+		WithPath("secret/data/app").
+		WithAccessToken("test-token").
+		Load(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if values["HOST"] != "example.com" {
+		t.Fatalf("unexpected values %+v", values)
+	}
+}
@@ -0,0 +1,99 @@
+package loader
+
+import (
+	"context"
+	"time"
+)
+
+// -----------------------------------------------------------------------------
+
+const (
+	vaultLeaseRetryDelay = 10 * time.Second
+)
+
+// -----------------------------------------------------------------------------
+
+// Watch returns a channel that receives a signal before the leases of the secrets read in the last load expire, so
+// they can be read again. The channel is closed when the context is done.
+//
+// Leases only cover changes of dynamic secrets, so, if some path did not return a leased secret in the last load, nil
+// is returned and the loader must be polled.
+func (l *Vault) Watch(ctx context.Context) <-chan struct{} {
+	l.leaseMtx.Lock()
+	leased := l.leased
+	l.leaseMtx.Unlock()
+
+	if l.err != nil || !leased {
+		return nil
+	}
+
+	ch := make(chan struct{}, 1)
+
+	go func() {
+		defer close(ch)
+
+		for {
+			l.leaseMtx.Lock()
+			renewAt := l.leaseRenewAt
+			updatedCh := l.getLeaseUpdatedCh()
+			l.leaseMtx.Unlock()
+
+			timer := time.NewTimer(time.Until(renewAt))
+
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+
+			case <-updatedCh:
+				timer.Stop()
+
+			case <-timer.C:
+				// If the next load fails to update the lease, signal again after a while
+				l.leaseMtx.Lock()
+				if l.leaseUpdatedCh == updatedCh {
+					l.leaseRenewAt = time.Now().Add(vaultLeaseRetryDelay)
+				}
+				l.leaseMtx.Unlock()
+
+				select {
+				case ch <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+
+	// Done
+	return ch
+}
+
+// -----------------------------------------------------------------------------
+
+// setLease stores the lease duration of the secret that expires first and wakes up the watcher
+func (l *Vault) setLease(leased bool, ttl time.Duration) {
+	l.leaseMtx.Lock()
+	defer l.leaseMtx.Unlock()
+
+	l.leased = leased
+	if leased {
+		// Like the token monitor, allow 80-90% of the lease duration to elapse
+		l.leaseRenewAt = time.Now().Add(ttl - calculateGracePeriod(ttl))
+	} else {
+		// The secrets are no longer leased, so keep reloading them periodically
+		l.leaseRenewAt = time.Now().Add(vaultLeaseRetryDelay)
+	}
+
+	if l.leaseUpdatedCh != nil {
+		close(l.leaseUpdatedCh)
+		l.leaseUpdatedCh = nil
+	}
+}
+
+// getLeaseUpdatedCh returns a channel that is closed when the lease is updated. The lease mutex must be held.
+func (l *Vault) getLeaseUpdatedCh() chan struct{} {
+	if l.leaseUpdatedCh == nil {
+		l.leaseUpdatedCh = make(chan struct{})
+	}
+	return l.leaseUpdatedCh
+}
@@ -0,0 +1,75 @@
+package loader_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mxmauro/configreader/loader"
+)
+
+// -----------------------------------------------------------------------------
+
+func TestVaultWatchLeaseExpiration(t *testing.T) {
+	// Create a fake Vault server that returns dynamic secrets with a short lease
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" && r.URL.Path == "/v1/database/creds/app" && r.Header.Get("X-Vault-Token") == "test-token" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"lease_id":       "database/creds/app/1234",
+				"lease_duration": 2,
+				"renewable":      true,
+				"data": map[string]interface{}{
+					"DB_USERNAME": "user",
+					"DB_PASSWORD": "password",
+				},
+			})
+			return
+		}
+
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{ "errors": [ "permission denied" ] }`))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	l := loader.NewVault().
+		WithHost(server.Listener.Addr().String()).
+		WithPath("database/creds/app").
+		WithAccessToken("test-token")
+
+	// Leases are only known after loading
+	if l.Watch(ctx) != nil {
+		t.Fatalf("loader must not be watchable before loading")
+	}
+
+	values, err := l.Load(ctx)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if values["DB_USERNAME"] != "user" {
+		t.Fatalf("unexpected values %+v", values)
+	}
+
+	ch := l.Watch(ctx)
+	if ch == nil {
+		t.Fatalf("unable to watch leases")
+	}
+
+	// The signal must arrive before the lease expires
+	start := time.Now()
+	select {
+	case <-ch:
+		if time.Since(start) >= 2*time.Second {
+			t.Fatalf("signal received after lease expiration")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("lease expiration not signaled")
+	}
+}
@@ -0,0 +1,48 @@
+package loader
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mxmauro/configreader/model"
+)
+
+// -----------------------------------------------------------------------------
+
+// watchLoader starts watching the provided loader if it implements model.Watcher, else returns nil
+func watchLoader(ctx context.Context, l model.Loader) <-chan struct{} {
+	if w, ok := l.(model.Watcher); ok {
+		return w.Watch(ctx)
+	}
+	return nil
+}
+
+// mergeWatchChannels returns a channel that receives a signal each time one of the provided channels does. The
+// returned channel is closed, and the cancel function called, when all of them are closed.
+func mergeWatchChannels(chs []<-chan struct{}, cancel context.CancelFunc) <-chan struct{} {
+	wg := sync.WaitGroup{}
+	ch := make(chan struct{}, 1)
+
+	for _, _ch := range chs {
+		wg.Add(1)
+		go func(_ch <-chan struct{}) {
+			defer wg.Done()
+
+			for range _ch {
+				select {
+				case ch <- struct{}{}:
+				default:
+				}
+			}
+		}(_ch)
+	}
+
+	go func() {
+		wg.Wait()
+		cancel()
+		close(ch)
+	}()
+
+	// Done
+	return ch
+}
@@ -0,0 +1,44 @@
+package loader
+
+import (
+	"errors"
+
+	"github.com/mxmauro/configreader/model"
+	"gopkg.in/yaml.v3"
+)
+
+// -----------------------------------------------------------------------------
+
+func isYAML(data []byte) bool {
+	var doc yaml.Node
+
+	// Try to guess a valid YAML document with a mapping as its root
+	err := yaml.Unmarshal(data, &doc)
+	if err != nil || doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return false
+	}
+	return doc.Content[0].Kind == yaml.MappingNode
+}
+
+func parseYAML(data []byte) (model.Values, error) {
+	var raw interface{}
+
+	err := yaml.Unmarshal(data, &raw)
+	if err != nil {
+		return nil, err
+	}
+
+	// An empty document
+	if raw == nil {
+		return make(model.Values), nil
+	}
+
+	// Normalize decoded values, so they look like the ones decoded from a JSON object
+	m, ok := normalizeDecodedValue(raw).(map[string]interface{})
+	if !ok {
+		return nil, errors.New("yaml document root is not a mapping")
+	}
+
+	// Done
+	return m, nil
+}
@@ -0,0 +1,119 @@
+package configreader_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mxmauro/configreader"
+	"github.com/mxmauro/configreader/loader"
+	"github.com/mxmauro/configreader/model"
+)
+
+// -----------------------------------------------------------------------------
+
+type MapsTest struct {
+	Features map[string]bool            `config:"FEATURE_*"`
+	Limits   map[string]int             `config:"TEST_LIMITS"`
+	Weights  map[int]float64            `config:"TEST_WEIGHTS"`
+	Backends map[string]MapsBackendTest `config:"TEST_BACKENDS"`
+}
+
+type MapsPrefixedStructTest struct {
+	Backends map[string]MapsBackendTest `config:"BACKEND_*"`
+}
+
+type MapsKeyTagsTest struct {
+	Sizes map[int]int `config:"TEST_SIZES" type:"memory"`
+}
+
+type MapsBackendTest struct {
+	Url     string `config:"url"`
+	Retries int    `config:"retries" default:"3"`
+}
+
+// -----------------------------------------------------------------------------
+
+func TestMaps(t *testing.T) {
+	// Load configuration
+	settings, err := configreader.New[MapsTest]().
+		WithLoader(loader.NewMemory().WithData(model.Values{
+			"FEATURE_A": "true",
+			"FEATURE_B": "no",
+			"TEST_LIMITS": map[string]interface{}{
+				"cpu":    2,
+				"memory": "512",
+			},
+			"TEST_WEIGHTS": map[string]interface{}{
+				"1": 0.25,
+				"2": 0.75,
+			},
+			"TEST_BACKENDS": map[string]interface{}{
+				"primary": map[string]interface{}{
+					"url":     "http://10.0.0.1",
+					"retries": 5,
+				},
+				"secondary": map[string]interface{}{
+					"url": "http://10.0.0.2",
+				},
+			},
+		})).
+		Load(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	// Check if settings are the expected
+	expected := MapsTest{
+		Features: map[string]bool{"A": true, "B": false},
+		Limits:   map[string]int{"cpu": 2, "memory": 512},
+		Weights:  map[int]float64{1: 0.25, 2: 0.75},
+		Backends: map[string]MapsBackendTest{
+			"primary":   {Url: "http://10.0.0.1", Retries: 5},
+			"secondary": {Url: "http://10.0.0.2", Retries: 3},
+		},
+	}
+	if !reflect.DeepEqual(settings, &expected) {
+		t.Fatalf("settings mismatch")
+	}
+}
+
+func TestMapsPrefixedStructElements(t *testing.T) {
+	// Prefixed keys only provide scalar values, so they cannot populate struct elements
+	_, err := configreader.New[MapsPrefixedStructTest]().
+		WithLoader(loader.NewMemory().WithData(model.Values{
+			"BACKEND_PRIMARY": "http://10.0.0.1",
+			"url":             "http://global",
+		})).
+		Load(context.Background())
+	if err == nil {
+		t.Fatalf("unexpected success")
+	}
+	if !strings.Contains(err.Error(), `Backends["PRIMARY"]`) {
+		t.Fatalf("unexpected error [err=%v]", err)
+	}
+}
+
+func TestMapsKeysIgnoreFieldTags(t *testing.T) {
+	// The memory type applies to the elements only, so negative keys are valid
+	settings, err := configreader.New[MapsKeyTagsTest]().
+		WithLoader(loader.NewMemory().WithData(model.Values{
+			"TEST_SIZES": map[string]interface{}{
+				"-1": "1KiB",
+				"2":  "2KiB",
+			},
+		})).
+		Load(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	// Check if settings are the expected
+	expected := MapsKeyTagsTest{
+		Sizes: map[int]int{-1: 1024, 2: 2048},
+	}
+	if !reflect.DeepEqual(settings, &expected) {
+		t.Fatalf("settings mismatch")
+	}
+}
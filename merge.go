@@ -0,0 +1,158 @@
+package configreader
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mxmauro/configreader/internal/helpers"
+	"github.com/mxmauro/configreader/model"
+)
+
+// -----------------------------------------------------------------------------
+
+// MergeStrategy specifies how the values provided by a source are merged with the ones provided by sources with lower
+// precedence.
+type MergeStrategy int
+
+const (
+	// MergeOverride replaces existing values. This is the default strategy.
+	MergeOverride MergeStrategy = iota
+	// MergeFillMissing only adds values not provided by previous sources
+	MergeFillMissing
+	// MergeErrorOnConflict fails if a previous source provided a different value for the same key
+	MergeErrorOnConflict
+)
+
+// LoaderOptions specifies how the values provided by a source are merged.
+type LoaderOptions struct {
+	// Priority establishes the order in which sources are merged. Sources with higher priority are merged last, so, by
+	// default, their values override the ones with lower priority. Sources with the same priority are merged in the
+	// order they were added and environment variables are merged after all the loaders with the same priority.
+	Priority int
+	// Strategy specifies how values are merged with the ones from previous sources
+	Strategy MergeStrategy
+	// Timeout limits the time the loader can take to load its values. Zero means no limit. Not applicable to
+	// environment variables.
+	Timeout time.Duration
+}
+
+type loaderEntry struct {
+	l    model.Loader
+	opts LoaderOptions
+}
+
+type mergeSource struct {
+	index    int // -1 for environment variables
+	opts     LoaderOptions
+	values   model.Values
+	cachedAt time.Time
+}
+
+// -----------------------------------------------------------------------------
+
+func sortMergeSources(sources []mergeSource) {
+	sort.SliceStable(sources, func(i, j int) bool {
+		if sources[i].opts.Priority != sources[j].opts.Priority {
+			return sources[i].opts.Priority < sources[j].opts.Priority
+		}
+		// Environment variables go after loaders with the same priority
+		return sources[i].index >= 0 && sources[j].index < 0
+	})
+}
+
+// mergeValues merges the source values into dst. Nested objects are merged recursively and the origin of each changed
+// value is stored in origins.
+func mergeValues(dst model.Values, src model.Values, strategy MergeStrategy, origin valueOrigin, flattenedKeys map[string]struct{}, origins map[string]*valueOrigin) error {
+	for k, v := range src {
+		keyOrigin := origin
+		_, keyOrigin.flattened = flattenedKeys[k]
+
+		existing, exists := dst[k]
+		if !exists {
+			dst[k] = v
+			origins[k] = keyOrigin.withValue(v, false)
+			continue
+		}
+
+		merged, changed, err := mergeValue(existing, v, strategy, k, &keyOrigin, origins)
+		if err != nil {
+			return err
+		}
+		if changed {
+			dst[k] = merged
+			if _, isMap := helpers.ToStringMap(merged); !isMap {
+				clearNestedOrigins(origins, k)
+				origins[k] = keyOrigin.withValue(v, false)
+			}
+		}
+	}
+
+	// Done
+	return nil
+}
+
+func mergeValue(existing interface{}, v interface{}, strategy MergeStrategy, path string, origin *valueOrigin, origins map[string]*valueOrigin) (interface{}, bool, error) {
+	existingMap, existingIsMap := helpers.ToStringMap(existing)
+	m, isMap := helpers.ToStringMap(v)
+	if existingIsMap && isMap {
+		// Merge objects into a copy so the original ones are not modified
+		merged := make(map[string]interface{}, len(existingMap)+len(m))
+		for k, elem := range existingMap {
+			merged[k] = elem
+		}
+
+		changed := false
+		for k, elem := range m {
+			existingElem, exists := merged[k]
+			if !exists {
+				merged[k] = elem
+				origins[path+"."+k] = origin.withValue(elem, true)
+				changed = true
+				continue
+			}
+
+			mergedElem, elemChanged, err := mergeValue(existingElem, elem, strategy, path+"."+k, origin, origins)
+			if err != nil {
+				return nil, false, err
+			}
+			if elemChanged {
+				merged[k] = mergedElem
+				if _, elemIsMap := helpers.ToStringMap(mergedElem); !elemIsMap {
+					clearNestedOrigins(origins, path+"."+k)
+					origins[path+"."+k] = origin.withValue(elem, true)
+				}
+				changed = true
+			}
+		}
+		if !changed {
+			return existing, false, nil
+		}
+		return merged, true, nil
+	}
+
+	// Non-object values
+	switch strategy {
+	case MergeFillMissing:
+		return existing, false, nil
+
+	case MergeErrorOnConflict:
+		if !reflect.DeepEqual(existing, v) {
+			return nil, false, fmt.Errorf("conflicting values found for key \"%s\"", path)
+		}
+		return existing, false, nil
+	}
+	return v, true, nil
+}
+
+// clearNestedOrigins removes the origins of values inside an object that was replaced
+func clearNestedOrigins(origins map[string]*valueOrigin, path string) {
+	prefix := path + "."
+	for k, origin := range origins {
+		if origin.nested && strings.HasPrefix(k, prefix) {
+			delete(origins, k)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package configreader_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mxmauro/configreader"
+	"github.com/mxmauro/configreader/internal/testhelpers"
+	"github.com/mxmauro/configreader/loader"
+	"github.com/mxmauro/configreader/model"
+)
+
+// -----------------------------------------------------------------------------
+
+type MergeTest struct {
+	Host     string `config:"MERGE_TEST_HOST"`
+	Password string `config:"MERGE_TEST_PASSWORD"`
+	Timeout  int    `config:"MERGE_TEST_TIMEOUT"`
+	DbHost   string `config:"db.host"`
+	DbPort   int    `config:"db.port"`
+}
+
+// -----------------------------------------------------------------------------
+
+func TestMergePriority(t *testing.T) {
+	defer testhelpers.ScopedEnvVars(map[string]string{
+		"MERGE_TEST_HOST":     "env-host",
+		"MERGE_TEST_PASSWORD": "env-password",
+	})()
+
+	// A loader with higher priority must override environment variables
+	settings, report, err := configreader.New[MergeTest]().
+		WithLoaderOptions(loader.NewMemory().WithData(model.Values{
+			"MERGE_TEST_PASSWORD": "secret-password",
+		}), configreader.LoaderOptions{
+			Priority: 1,
+		}).
+		WithLoader(loader.NewMemory().WithData(model.Values{
+			"MERGE_TEST_HOST":     "memory-host",
+			"MERGE_TEST_PASSWORD": "memory-password",
+			"MERGE_TEST_TIMEOUT":  10,
+			"db": map[string]interface{}{
+				"host": "localhost",
+				"port": 5432,
+			},
+		})).
+		WithLoaderOptions(loader.NewMemory().WithData(model.Values{
+			"MERGE_TEST_TIMEOUT": 20,
+			"db": map[string]interface{}{
+				"port": 6543,
+			},
+		}), configreader.LoaderOptions{
+			Strategy: configreader.MergeFillMissing,
+		}).
+		WithLoader(loader.NewMemory().WithData(model.Values{
+			"db": map[string]interface{}{
+				"host": "db.example.com",
+			},
+		})).
+		LoadWithReport(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if settings.Host != "env-host" || settings.Password != "secret-password" || settings.Timeout != 10 {
+		t.Fatalf("unexpected settings [host=%v, password=%v, timeout=%v]", settings.Host, settings.Password,
+			settings.Timeout)
+	}
+
+	// Nested objects must be merged instead of replaced
+	if settings.DbHost != "db.example.com" || settings.DbPort != 5432 {
+		t.Fatalf("unexpected settings [db.host=%v, db.port=%v]", settings.DbHost, settings.DbPort)
+	}
+
+	checkReportField(t, report, "Password", "Memory", 0, false)
+	checkReportField(t, report, "DbHost", "Memory", 3, false)
+	checkReportField(t, report, "DbPort", "Memory", 1, false)
+}
+
+func TestMergeErrorOnConflict(t *testing.T) {
+	cr := func(host string) *configreader.ConfigReader[MergeTest] {
+		return configreader.New[MergeTest]().
+			WithLoader(loader.NewMemory().WithData(model.Values{
+				"MERGE_TEST_HOST":     "localhost",
+				"MERGE_TEST_PASSWORD": "password",
+				"MERGE_TEST_TIMEOUT":  10,
+				"db": map[string]interface{}{
+					"host": "localhost",
+					"port": 5432,
+				},
+			})).
+			WithLoaderOptions(loader.NewMemory().WithData(model.Values{
+				"MERGE_TEST_HOST": host,
+			}), configreader.LoaderOptions{
+				Strategy: configreader.MergeErrorOnConflict,
+			}).
+			WithDisableAllEnvVarOverride()
+	}
+
+	_, err := cr("localhost").Load(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	_, err = cr("other").Load(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "MERGE_TEST_HOST") {
+		t.Fatalf("conflict not detected [err=%v]", err)
+	}
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"context"
+	"time"
 )
 
 // -----------------------------------------------------------------------------
@@ -10,3 +11,23 @@ import (
 type Loader interface {
 	Load(ctx context.Context) (data Values, err error)
 }
+
+// Wrapper defines the spec of a loader that delegates the work to other loaders.
+type Wrapper interface {
+	// Unwrap returns the loader that provided the values in the last successful load or nil if none
+	Unwrap() Loader
+}
+
+// Cache defines the spec of a loader that can provide values stored in a local cache when the source fails.
+type Cache interface {
+	// CachedAt returns the time the values returned by the last load were stored in the cache or false if they were
+	// provided by the source
+	CachedAt() (time.Time, bool)
+}
+
+// Watcher defines the spec of a loader that can notify when its source changes, so it does not need to be polled.
+type Watcher interface {
+	// Watch returns a channel that receives a signal each time the source changes or nil if the source cannot be
+	// watched. The channel is closed when the context is done.
+	Watch(ctx context.Context) <-chan struct{}
+}
@@ -2,11 +2,13 @@ package configreader
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"sync"
 	"time"
 
 	"github.com/mxmauro/channelcontext"
+	"github.com/mxmauro/configreader/model"
 )
 
 // -----------------------------------------------------------------------------
@@ -23,11 +25,25 @@ type Monitor[T any] struct {
 	stopCh chan struct{}
 
 	settingsHash [64]byte
+	settings     *T
+	report       *Report
+
+	reloadGuards     []ReloadGuard[T]
+	changesCallbacks []changesCallback[T]
+}
+
+// ReloadGuard is a function to call when the re-loader detects a change in the configuration settings, before they
+// become current. Return an error to reject the new settings.
+type ReloadGuard[T any] func(ctx context.Context, previous *T, settings *T) error
+
+type changesCallback[T any] struct {
+	callback SettingsChangesCallback[T]
+	paths    []string
 }
 
 // -----------------------------------------------------------------------------
 
-// NewMonitor creates a new configuration settings change monitor
+// NewMonitor creates a new configuration settings change monitor. The callback is optional if a Holder is used.
 func NewMonitor[T any](pollInterval time.Duration, callback SettingsChangedCallback[T]) *Monitor[T] {
 	return &Monitor[T]{
 		pollInterval: pollInterval,
@@ -36,6 +52,31 @@ func NewMonitor[T any](pollInterval time.Duration, callback SettingsChangedCallb
 	}
 }
 
+// WithReloadGuard adds a guard that can reject reloaded settings before they become current. If a guard returns an
+// error, the previous settings are kept, the callback is called with a ReloadRejectedError and the new settings are
+// checked again on the next poll.
+func (m *Monitor[T]) WithReloadGuard(guard ReloadGuard[T]) *Monitor[T] {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.reloadGuards = append(m.reloadGuards, guard)
+	return m
+}
+
+// WithChangesCallback adds a callback that receives the previous settings and the list of changed fields each time
+// the configuration settings change. If paths are specified, like "Database" or "Server.Port", the callback is only
+// called if one of those fields, or a field inside them, changed, and only receives the matching changes.
+func (m *Monitor[T]) WithChangesCallback(callback SettingsChangesCallback[T], paths ...string) *Monitor[T] {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.changesCallbacks = append(m.changesCallbacks, changesCallback[T]{
+		callback: callback,
+		paths:    paths,
+	})
+	return m
+}
+
 // Destroy stops a running configuration settings monitor and destroys it
 func (m *Monitor[T]) Destroy() {
 	if m.stopCh != nil {
@@ -47,13 +88,15 @@ func (m *Monitor[T]) Destroy() {
 	m.stopCh = nil
 }
 
-func (m *Monitor[T]) start(cr *ConfigReader[T], settingsHash [64]byte) error {
+func (m *Monitor[T]) start(cr *ConfigReader[T], settings *T, report *Report, settingsHash [64]byte) error {
 	m.mtx.Lock()
 	defer m.mtx.Unlock()
 
 	// Check if the configuration reader is already attached to this monitor
 	if m.attachedCr == cr {
 		copy(m.settingsHash[:], settingsHash[:]) // Just update the encoded settings hash
+		m.settings = settings
+		m.report = report
 		return nil
 	}
 
@@ -65,29 +108,56 @@ func (m *Monitor[T]) start(cr *ConfigReader[T], settingsHash [64]byte) error {
 	// Attach and create a copy of the encoded settings hash
 	m.attachedCr = cr
 	copy(m.settingsHash[:], settingsHash[:])
+	m.settings = settings
+	m.report = report
 
-	// Start polling
+	// Watch loaders before returning so changes made right after the load are not missed, and only poll if
+	// some loader cannot be watched
 	m.stopCh = make(chan struct{})
+	watchCtx, cancelWatchCtx := channelcontext.New[struct{}](m.stopCh)
+	changedCh, watchLostCh, allWatched := m.watchLoaders(watchCtx)
+
+	// Start monitoring
 	m.wg.Add(1)
-	go m.worker()
+	go m.worker(changedCh, watchLostCh, allWatched, cancelWatchCtx)
 
 	// Done
 	return nil
 }
 
-func (m *Monitor[T]) worker() {
+func (m *Monitor[T]) worker(changedCh <-chan struct{}, watchLostCh <-chan struct{}, allWatched bool, cancelWatchCtx context.CancelFunc) {
 	defer m.wg.Done()
+	defer cancelWatchCtx()
+
+	// Poll if some loader is not watched or the last reload failed or was rejected, so it is retried even if no
+	// further changes are notified
+	retry := false
 
 MainLoop:
 	for {
+		var pollCh <-chan time.Time
+		var quit bool
+
+		if !allWatched || retry {
+			pollCh = time.After(m.pollInterval)
+		}
+
 		select {
 		case <-m.stopCh:
 			break MainLoop
 
-		case <-time.After(m.pollInterval):
-			if m.doReload() {
-				break MainLoop
-			}
+		case <-pollCh:
+			quit, retry = m.doReload()
+
+		case <-changedCh:
+			quit, retry = m.doReload()
+
+		case <-watchLostCh:
+			// A loader stopped notifying changes, so fall back to polling
+			allWatched = false
+		}
+		if quit {
+			break MainLoop
 		}
 	}
 
@@ -97,39 +167,126 @@ MainLoop:
 	m.mtx.Unlock()
 }
 
-func (m *Monitor[T]) doReload() bool {
+// watchLoaders returns a channel that receives a signal when the source of a loader implementing model.Watcher
+// changes, another one that receives a signal if a loader stops being watched before the context is done, and true if
+// all loaders are watched
+func (m *Monitor[T]) watchLoaders(ctx context.Context) (<-chan struct{}, <-chan struct{}, bool) {
+	changedCh := make(chan struct{}, 1)
+	watchLostCh := make(chan struct{}, 1)
+	allWatched := len(m.attachedCr.loader) > 0
+
+	for _, entry := range m.attachedCr.loader {
+		var ch <-chan struct{}
+
+		if w, ok := entry.l.(model.Watcher); ok {
+			ch = w.Watch(ctx)
+		}
+		if ch == nil {
+			allWatched = false
+			continue
+		}
+
+		// Forward signals
+		go func(ch <-chan struct{}) {
+			for range ch {
+				select {
+				case changedCh <- struct{}{}:
+				default:
+				}
+			}
+
+			if ctx.Err() == nil {
+				select {
+				case watchLostCh <- struct{}{}:
+				default:
+				}
+			}
+		}(ch)
+	}
+
+	// Done
+	return changedCh, watchLostCh, allWatched
+}
+
+// doReload reloads the settings and returns true if the monitor is stopping and true if the reload failed or was
+// rejected and must be retried
+func (m *Monitor[T]) doReload() (bool, bool) {
 	stopCtx, cancelStopCtx := channelcontext.New[struct{}](m.stopCh)
 	defer cancelStopCtx()
 
 	// Load the whole data
-	settings, settingsHash, err := m.attachedCr.load(stopCtx)
+	settings, report, settingsHash, err := m.attachedCr.load(stopCtx)
 	if err != nil {
 		select {
 		case <-stopCtx.Done():
-			return true
+			return true, false
 		default:
-			m.callback(nil, err)
-			return false
+			if m.callback != nil {
+				m.callback(nil, err)
+			}
+			return false, true
 		}
 	}
 
-	changed := false
-
 	m.mtx.Lock()
 
 	// If encoded settings are the same, do nothing
-	if !bytes.Equal(settingsHash[:], m.settingsHash[:]) {
-		copy(m.settingsHash[:], settingsHash[:])
-		changed = true
+	if bytes.Equal(settingsHash[:], m.settingsHash[:]) {
+		m.mtx.Unlock()
+		return false, false
 	}
 
+	previousSettings := m.settings
+	previousReport := m.report
+	reloadGuards := m.reloadGuards
+	changesCallbacks := m.changesCallbacks
+
 	m.mtx.Unlock()
 
-	// Call the callback if settings changed
-	if changed {
+	// Let the guards validate the new settings before making them current. If one of them rejects the settings, keep
+	// the previous ones, so the new settings are checked again on the next poll.
+	for _, guard := range reloadGuards {
+		err = guard(stopCtx, previousSettings, settings)
+		if err != nil {
+			select {
+			case <-stopCtx.Done():
+				return true, false
+			default:
+				if m.callback != nil {
+					m.callback(nil, &ReloadRejectedError{
+						Err: err,
+					})
+				}
+				return false, true
+			}
+		}
+	}
+
+	// Commit the new settings
+	m.mtx.Lock()
+	copy(m.settingsHash[:], settingsHash[:])
+	m.settings = settings
+	m.report = report
+	m.mtx.Unlock()
+
+	// Update the holder and call the callbacks
+	if m.attachedCr.holder != nil {
+		m.attachedCr.holder.set(settings)
+	}
+	if m.callback != nil {
 		m.callback(settings, nil)
 	}
 
-	// Do nothing
-	return false
+	if len(changesCallbacks) > 0 && previousSettings != nil {
+		changes := diffSettings(previousSettings, previousReport, settings, report)
+		for _, cc := range changesCallbacks {
+			filteredChanges := filterChanges(changes, cc.paths)
+			if len(filteredChanges) > 0 {
+				cc.callback(previousSettings, settings, filteredChanges)
+			}
+		}
+	}
+
+	// Done
+	return false, false
 }
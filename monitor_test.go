@@ -2,6 +2,7 @@ package configreader_test
 
 import (
 	"context"
+	"errors"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -54,3 +55,112 @@ func TestMonitor(t *testing.T) {
 		atomic.AddInt32(&value, 1)
 	}
 }
+
+func TestMonitorRetryRejectedWatchedReload(t *testing.T) {
+	var value int32 = 1
+	var rejections int32
+
+	w := &watchedLoaderTest{
+		value: &value,
+		ch:    make(chan struct{}, 1),
+	}
+
+	errCh := make(chan error, 10)
+	settingsCh := make(chan *MonitorTest, 10)
+
+	settingsMonitor := configreader.NewMonitor[MonitorTest](100*time.Millisecond, func(settings *MonitorTest, loadErr error) {
+		if loadErr != nil {
+			errCh <- loadErr
+		} else {
+			settingsCh <- settings
+		}
+	}).WithReloadGuard(func(_ context.Context, _ *MonitorTest, _ *MonitorTest) error {
+		if atomic.AddInt32(&rejections, 1) == 1 {
+			return errors.New("cannot apply new settings")
+		}
+		return nil
+	})
+	defer settingsMonitor.Destroy()
+
+	_, err := configreader.New[MonitorTest]().
+		WithLoader(w).
+		WithMonitor(settingsMonitor).
+		Load(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	// Notify a single change. The rejected settings must be checked again without further notifications.
+	atomic.StoreInt32(&value, 2)
+	w.ch <- struct{}{}
+
+	select {
+	case <-errCh:
+	case <-time.After(3 * time.Second):
+		t.Fatalf("rejection not received")
+	}
+	select {
+	case settings := <-settingsCh:
+		if settings.Value != 2 {
+			t.Fatalf("unexpected value %d (should be 2)", settings.Value)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("rejected settings not retried")
+	}
+}
+
+func TestMonitorWatchLost(t *testing.T) {
+	var value int32 = 1
+
+	w := &watchedLoaderTest{
+		value: &value,
+		ch:    make(chan struct{}, 1),
+	}
+
+	settingsCh := make(chan *MonitorTest, 10)
+
+	settingsMonitor := configreader.NewMonitor[MonitorTest](100*time.Millisecond, func(settings *MonitorTest, loadErr error) {
+		if loadErr == nil {
+			settingsCh <- settings
+		}
+	})
+	defer settingsMonitor.Destroy()
+
+	_, err := configreader.New[MonitorTest]().
+		WithLoader(w).
+		WithMonitor(settingsMonitor).
+		Load(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	// Stop watching and change the value without notifying. The monitor must fall back to polling.
+	close(w.ch)
+	atomic.StoreInt32(&value, 2)
+
+	select {
+	case settings := <-settingsCh:
+		if settings.Value != 2 {
+			t.Fatalf("unexpected value %d (should be 2)", settings.Value)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("settings change not received")
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+type watchedLoaderTest struct {
+	value *int32
+	ch    chan struct{}
+}
+
+func (l *watchedLoaderTest) Load(_ context.Context) (model.Values, error) {
+	return model.Values{
+		"TEST_VALUE": atomic.LoadInt32(l.value),
+	}, nil
+}
+
+func (l *watchedLoaderTest) Watch(_ context.Context) <-chan struct{} {
+	return l.ch
+}
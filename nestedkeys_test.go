@@ -0,0 +1,43 @@
+package configreader_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mxmauro/configreader"
+	"github.com/mxmauro/configreader/loader"
+)
+
+// -----------------------------------------------------------------------------
+
+type NestedKeysTest struct {
+	Host     string `config:"db.host"`
+	Port     int    `config:"DB_PORT"`
+	Username string `config:"DB_CREDENTIALS_USERNAME"`
+}
+
+// -----------------------------------------------------------------------------
+
+func TestNestedKeys(t *testing.T) {
+	// Load configuration from a JSON document
+	settings, err := configreader.New[NestedKeysTest]().
+		WithLoader(loader.NewMemory().WithData(map[string]interface{}{
+			"db": map[string]interface{}{
+				"host": "127.0.0.1",
+				"port": 5432,
+				"credentials": map[string]interface{}{
+					"username": "user",
+				},
+			},
+		})).
+		WithFlattenedKeys("_").
+		Load(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	// Check if settings are the expected
+	if settings.Host != "127.0.0.1" || settings.Port != 5432 || settings.Username != "user" {
+		t.Fatalf("settings mismatch")
+	}
+}
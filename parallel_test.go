@@ -0,0 +1,83 @@
+package configreader_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mxmauro/configreader"
+	"github.com/mxmauro/configreader/loader"
+	"github.com/mxmauro/configreader/model"
+)
+
+// -----------------------------------------------------------------------------
+
+type ParallelTest struct {
+	Name string `config:"PARALLEL_TEST_NAME"`
+}
+
+// -----------------------------------------------------------------------------
+
+func TestParallelLoaders(t *testing.T) {
+	start := time.Now()
+
+	// The first loader takes longer but the second one must still override its values
+	settings, err := configreader.New[ParallelTest]().
+		WithLoader(newParallelTestLoader(500*time.Millisecond, "first", nil)).
+		WithLoader(newParallelTestLoader(300*time.Millisecond, "second", nil)).
+		Load(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if settings.Name != "second" {
+		t.Fatalf("unexpected settings [name=%v]", settings.Name)
+	}
+	if elapsed := time.Since(start); elapsed >= 800*time.Millisecond {
+		t.Fatalf("loaders were not executed concurrently [elapsed=%v]", elapsed)
+	}
+}
+
+func TestParallelLoadersErrors(t *testing.T) {
+	brokenErr := errors.New("broken source")
+
+	_, err := configreader.New[ParallelTest]().
+		WithLoader(newParallelTestLoader(0, "first", brokenErr)).
+		WithLoader(newParallelTestLoader(0, "second", nil)).
+		WithLoaderOptions(newParallelTestLoader(time.Second, "third", nil), configreader.LoaderOptions{
+			Timeout: 100 * time.Millisecond,
+		}).
+		Load(context.Background())
+	if err == nil {
+		t.Fatalf("error not returned")
+	}
+
+	var loadErr *configreader.LoadError
+	if !errors.As(err, &loadErr) {
+		t.Fatalf("unexpected error [err=%v]", err)
+	}
+	if len(loadErr.Failures) != 2 || loadErr.Failures[0].LoaderIndex != 0 || loadErr.Failures[1].LoaderIndex != 2 {
+		t.Fatalf("unexpected failures [err=%v]", err)
+	}
+	if !errors.Is(err, brokenErr) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("unexpected wrapped errors [err=%v]", err)
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+func newParallelTestLoader(delay time.Duration, name string, err error) model.Loader {
+	return loader.NewCallback().WithCallback(func(ctx context.Context) (model.Values, error) {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+		if err != nil {
+			return nil, err
+		}
+		return model.Values{
+			"PARALLEL_TEST_NAME": name,
+		}, nil
+	})
+}
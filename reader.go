@@ -1,11 +1,13 @@
 package configreader
 
 import (
-	"bytes"
 	"context"
-	"crypto/sha512"
-	"encoding/gob"
+	"errors"
+	"fmt"
+	"reflect"
 	"slices"
+	"strings"
+	"sync"
 
 	"github.com/mxmauro/configreader/internal/helpers"
 	"github.com/mxmauro/configreader/loader"
@@ -16,11 +18,19 @@ import (
 
 // ConfigReader contains configurable loader options.
 type ConfigReader[T any] struct {
-	loader                []model.Loader
+	loader                []loaderEntry
+	envVarOptions         LoaderOptions
 	extendedValidator     ExtendedValidator[T]
 	disableEnvVarOverride []string
+	disableAllEnvVars     bool
+	envPrefix             string
+	knownEnvVarsOnly      bool
+	flattenSeparator      string
+	strict                bool
+	decoders              map[reflect.Type]DecodeFunc
 
 	monitor *Monitor[T]
+	holder  *Holder[T]
 
 	err error
 }
@@ -49,9 +59,36 @@ func New[T any]() *ConfigReader[T] {
 func (cr *ConfigReader[T]) WithLoader(l ...model.Loader) *ConfigReader[T] {
 	if cr.err == nil {
 		if cr.loader == nil {
-			cr.loader = make([]model.Loader, 0)
+			cr.loader = make([]loaderEntry, 0)
 		}
-		cr.loader = append(cr.loader, l...)
+		for _, _l := range l {
+			cr.loader = append(cr.loader, loaderEntry{
+				l: _l,
+			})
+		}
+	}
+	return cr
+}
+
+// WithLoaderOptions sets a content loader specifying its precedence and how its values are merged
+func (cr *ConfigReader[T]) WithLoaderOptions(l model.Loader, opts LoaderOptions) *ConfigReader[T] {
+	if cr.err == nil {
+		if cr.loader == nil {
+			cr.loader = make([]loaderEntry, 0)
+		}
+		cr.loader = append(cr.loader, loaderEntry{
+			l:    l,
+			opts: opts,
+		})
+	}
+	return cr
+}
+
+// WithEnvVarOptions sets the precedence of environment variables and how their values are merged. By default,
+// environment variables are merged after all the loaders with the same priority, overriding their values.
+func (cr *ConfigReader[T]) WithEnvVarOptions(opts LoaderOptions) *ConfigReader[T] {
+	if cr.err == nil {
+		cr.envVarOptions = opts
 	}
 	return cr
 }
@@ -75,6 +112,75 @@ func (cr *ConfigReader[T]) WithDisableEnvVarOverride(vars ...string) *ConfigRead
 	return cr
 }
 
+// WithDisableAllEnvVarOverride stops the loader from replacing values with environment variables. Variables can still
+// be referenced by ${} expansion.
+func (cr *ConfigReader[T]) WithDisableAllEnvVarOverride() *ConfigReader[T] {
+	if cr.err == nil {
+		cr.disableAllEnvVars = true
+	}
+	return cr
+}
+
+// WithEnvPrefix only allows environment variables starting with the given prefix to replace values. The prefix is
+// removed from the variable name before looking for the key to replace, so MYAPP_PORT replaces PORT if the prefix is
+// "MYAPP_".
+func (cr *ConfigReader[T]) WithEnvPrefix(prefix string) *ConfigReader[T] {
+	if cr.err == nil {
+		if len(prefix) > 0 {
+			cr.envPrefix = prefix
+		} else {
+			cr.err = errors.New("invalid prefix")
+		}
+	}
+	return cr
+}
+
+// WithKnownEnvVarsOnly only allows environment variables matching a key referenced by a `config` tag to replace
+// values.
+func (cr *ConfigReader[T]) WithKnownEnvVarsOnly() *ConfigReader[T] {
+	if cr.err == nil {
+		cr.knownEnvVarsOnly = true
+	}
+	return cr
+}
+
+// WithFlattenedKeys adds the content of nested objects as top-level keys by joining their path with the given separator
+// and converting it to uppercase, so {"db": {"host": "x"}} can be referenced as DB_HOST if the separator is "_".
+// Regardless of this option, nested values can always be referenced using dot notation like `config:"db.host"`.
+func (cr *ConfigReader[T]) WithFlattenedKeys(separator string) *ConfigReader[T] {
+	if cr.err == nil {
+		if len(separator) > 0 {
+			cr.flattenSeparator = separator
+		} else {
+			cr.err = errors.New("invalid separator")
+		}
+	}
+	return cr
+}
+
+// WithStrict makes Load fail if loaders provide keys not used by any field. Environment variables are not checked.
+func (cr *ConfigReader[T]) WithStrict() *ConfigReader[T] {
+	if cr.err == nil {
+		cr.strict = true
+	}
+	return cr
+}
+
+// WithDecoder sets a custom decoder for fields of the given type
+func (cr *ConfigReader[T]) WithDecoder(t reflect.Type, decoder DecodeFunc) *ConfigReader[T] {
+	if cr.err == nil {
+		if t != nil && decoder != nil {
+			if cr.decoders == nil {
+				cr.decoders = make(map[reflect.Type]DecodeFunc)
+			}
+			cr.decoders[t] = decoder
+		} else {
+			cr.err = errors.New("invalid decoder")
+		}
+	}
+	return cr
+}
+
 // WithMonitor sets a monitor that will inform about configuration settings changes
 //
 // NOTE: First send a value to stop monitoring and then wait until a value is received on the same channel
@@ -85,11 +191,26 @@ func (cr *ConfigReader[T]) WithMonitor(m *Monitor[T]) *ConfigReader[T] {
 	return cr
 }
 
+// WithHolder sets a holder that will keep the current configuration settings, updated on each load and by the
+// monitor, if one is set
+func (cr *ConfigReader[T]) WithHolder(h *Holder[T]) *ConfigReader[T] {
+	if cr.err == nil {
+		cr.holder = h
+	}
+	return cr
+}
+
 // Load settings from the specified source
 func (cr *ConfigReader[T]) Load(ctx context.Context) (*T, error) {
+	settings, _, err := cr.LoadWithReport(ctx)
+	return settings, err
+}
+
+// LoadWithReport loads settings from the specified source and returns a report describing where each value came from
+func (cr *ConfigReader[T]) LoadWithReport(ctx context.Context) (*T, *Report, error) {
 	// If an error was set by a With... function, return it
 	if cr.err != nil {
-		return nil, cr.err
+		return nil, nil, cr.err
 	}
 
 	// If no context was specified, use a default
@@ -98,100 +219,254 @@ func (cr *ConfigReader[T]) Load(ctx context.Context) (*T, error) {
 	}
 
 	// Load the whole data
-	settings, settingsHash, err := cr.load(ctx)
+	settings, report, settingsHash, err := cr.load(ctx)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	// Update holder if provided
+	if cr.holder != nil {
+		cr.holder.set(settings)
 	}
 
 	// Start re-loader goroutine if provided
 	if cr.monitor != nil {
-		err = cr.monitor.start(cr, settingsHash)
+		err = cr.monitor.start(cr, settings, report, settingsHash)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 
 	// Done
-	return settings, nil
+	return settings, report, nil
 }
 
-func (cr *ConfigReader[T]) load(ctx context.Context) (*T, [64]byte, error) {
+func (cr *ConfigReader[T]) load(ctx context.Context) (*T, *Report, [64]byte, error) {
 	var hash [64]byte
 
 	// Load the whole data
-	values, err := cr.loadValues(ctx)
+	values, origins, err := cr.loadValues(ctx)
 	if err != nil {
-		return nil, hash, err
+		return nil, nil, hash, err
 	}
 
 	// Decode settings
 	settings := new(T)
-	err = cr.fillFields(settings, values)
+	state := newFillState(origins)
+	err = cr.fillFields(settings, values, state)
 	if err != nil {
-		return nil, hash, err
+		return nil, nil, hash, err
+	}
+
+	// Check for unknown keys if running in strict mode
+	if cr.strict {
+		err = state.checkUnknownKeys(cr.flattenSeparator)
+		if err != nil {
+			return nil, nil, hash, err
+		}
 	}
 
 	// Validate settings
 	err = cr.validate(ctx, settings)
 	if err != nil {
-		return nil, hash, err
+		return nil, nil, hash, err
 	}
 
 	// Calculate hash
 	hash, err = cr.calcHash(settings)
 	if err != nil {
-		return nil, hash, err
+		return nil, nil, hash, err
 	}
 
 	// Done
-	return settings, hash, nil
+	return settings, state.report, hash, nil
 }
 
-func (cr *ConfigReader[T]) loadValues(ctx context.Context) (model.Values, error) {
+func (cr *ConfigReader[T]) loadValues(ctx context.Context) (model.Values, map[string]*valueOrigin, error) {
 	// Load the whole data
+	sources, err := cr.runLoaders(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Add environment variables
+	envVars := loader.GetEnvVars()
+	if !cr.disableAllEnvVars {
+		sources = append(sources, mergeSource{
+			index:  -1,
+			opts:   cr.envVarOptions,
+			values: cr.filterEnvVars(envVars),
+		})
+	}
+
+	// Merge values by precedence
+	sortMergeSources(sources)
+
 	mergedValues := make(model.Values)
-	for _, l := range cr.loader {
-		values, err := l.Load(ctx)
-		if err != nil {
-			return nil, err
+	origins := make(map[string]*valueOrigin)
+	for _, source := range sources {
+		origin := valueOrigin{
+			loaderIndex: source.index,
+			cachedAt:    source.cachedAt,
 		}
-		for k, v := range values {
-			mergedValues[k] = v
+		values := source.values
+		var flattenedKeys map[string]struct{}
+
+		if source.index >= 0 {
+			origin.loader = effectiveLoader(cr.loader[source.index].l)
+
+			if len(cr.flattenSeparator) > 0 {
+				values = helpers.FlattenValues(values, cr.flattenSeparator)
+				flattenedKeys = make(map[string]struct{})
+				for k := range values {
+					if _, ok := source.values[k]; !ok {
+						flattenedKeys[k] = struct{}{}
+					}
+				}
+			}
 		}
-	}
 
-	// Merge environment variables
-	for k, v := range loader.GetEnvVars() {
-		if !slices.Contains(cr.disableEnvVarOverride, k) {
-			mergedValues[k] = v
+		err := mergeValues(mergedValues, values, source.opts.Strategy, origin, flattenedKeys, origins)
+		if err != nil {
+			if origin.loader != nil {
+				return nil, nil, fmt.Errorf("unable to merge values from %s loader #%d [err=%v]", loaderName(origin.loader),
+					source.index+1, err)
+			}
+			return nil, nil, fmt.Errorf("unable to merge environment variables [err=%v]", err)
 		}
 	}
 
-	// Expand nested expressions
+	// Expand nested expressions. Environment variables can be referenced even if they do not override values, except
+	// the ones explicitly excluded.
+	lookupValues := make(model.Values, len(envVars)+len(mergedValues))
+	for name, v := range envVars {
+		if !cr.isEnvVarOverrideDisabled(name) {
+			lookupValues[name] = v
+		}
+	}
 	for k, v := range mergedValues {
-		replacement, replaced, err := helpers.Expand(v, mergedValues)
+		lookupValues[k] = v
+	}
+	for k, v := range mergedValues {
+		replacement, replaced, err := helpers.Expand(v, lookupValues)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		if replaced {
 			mergedValues[k] = replacement
+			origins[k].expanded = true
 		}
 	}
 
 	// Done
-	return mergedValues, nil
+	return mergedValues, origins, nil
 }
 
-func (cr *ConfigReader[T]) calcHash(setting *T) ([64]byte, error) {
-	buf := bytes.Buffer{}
-	encoder := gob.NewEncoder(&buf)
+// runLoaders executes all the loaders concurrently and returns their values in the order they were added
+func (cr *ConfigReader[T]) runLoaders(ctx context.Context) ([]mergeSource, error) {
+	sources := make([]mergeSource, len(cr.loader), len(cr.loader)+1)
+	errs := make([]error, len(cr.loader))
+
+	wg := sync.WaitGroup{}
+	for idx := range cr.loader {
+		wg.Add(1)
+
+		go func(idx int) {
+			defer wg.Done()
+
+			entry := cr.loader[idx]
+
+			loaderCtx := ctx
+			if entry.opts.Timeout > 0 {
+				var cancel context.CancelFunc
+
+				loaderCtx, cancel = context.WithTimeout(ctx, entry.opts.Timeout)
+				defer cancel()
+			}
+
+			values, err := entry.l.Load(loaderCtx)
+			if err == nil {
+				sources[idx] = mergeSource{
+					index:    idx,
+					opts:     entry.opts,
+					values:   values,
+					cachedAt: loaderCachedAt(entry.l),
+				}
+			} else {
+				if errors.Is(loaderCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+					err = fmt.Errorf("timeout [err=%w]", err)
+				}
+				errs[idx] = err
+			}
+		}(idx)
+	}
+	wg.Wait()
 
-	// Encode
-	err := encoder.Encode(setting)
-	if err != nil {
-		return [64]byte{}, err
+	// Report all failing loaders
+	var loadErr *LoadError
+	for idx, err := range errs {
+		if err != nil {
+			if loadErr == nil {
+				loadErr = &LoadError{
+					Failures: make([]LoadErrorFailure, 0),
+				}
+			}
+			loadErr.Failures = append(loadErr.Failures, LoadErrorFailure{
+				Loader:      cr.loader[idx].l,
+				LoaderIndex: idx,
+				Err:         err,
+			})
+		}
+	}
+	if loadErr != nil {
+		return nil, loadErr
+	}
+
+	// Done
+	return sources, nil
+}
+
+func (cr *ConfigReader[T]) filterEnvVars(envVars model.Values) model.Values {
+	var keys *configKeys
+
+	if cr.knownEnvVarsOnly {
+		keys = collectConfigKeys(reflect.TypeOf((*T)(nil)).Elem())
+	}
+
+	ret := make(model.Values)
+	for name, v := range envVars {
+		k := name
+		if len(cr.envPrefix) > 0 {
+			if len(k) <= len(cr.envPrefix) || !strings.HasPrefix(k, cr.envPrefix) {
+				continue
+			}
+			k = k[len(cr.envPrefix):]
+		}
+		if cr.isEnvVarOverrideDisabled(name) {
+			continue
+		}
+		if keys != nil && !keys.contains(k) {
+			continue
+		}
+		ret[k] = v
+	}
+
+	// Done
+	return ret
+}
+
+// isEnvVarOverrideDisabled returns true if the environment variable, with or without the prefix, was excluded
+func (cr *ConfigReader[T]) isEnvVarOverrideDisabled(name string) bool {
+	if slices.Contains(cr.disableEnvVarOverride, name) {
+		return true
 	}
+	if len(cr.envPrefix) > 0 && len(name) > len(cr.envPrefix) && strings.HasPrefix(name, cr.envPrefix) {
+		return slices.Contains(cr.disableEnvVarOverride, name[len(cr.envPrefix):])
+	}
+	return false
+}
 
+func (cr *ConfigReader[T]) calcHash(setting *T) ([64]byte, error) {
 	// Calculate hash
-	return sha512.Sum512(buf.Bytes()), nil
+	return helpers.HashValue(setting), nil
 }
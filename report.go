@@ -0,0 +1,199 @@
+package configreader
+
+import (
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/mxmauro/configreader/model"
+)
+
+// -----------------------------------------------------------------------------
+
+const (
+	// SourceEnvVar indicates the value was taken from an environment variable
+	SourceEnvVar = "env"
+	// SourceDefault indicates the value was taken from the field's default tag
+	SourceDefault = "default"
+)
+
+// -----------------------------------------------------------------------------
+
+// Report contains details about the origin of each loaded setting.
+type Report struct {
+	// Fields is keyed by the field path, i.e. "Server.Port"
+	Fields map[string]ReportField
+}
+
+// ReportField describes where the value of a field came from.
+type ReportField struct {
+	// Source is the name of the loader type that provided the value, like "File" or "Vault", or one of SourceEnvVar
+	// and SourceDefault
+	Source string
+	// Loader is the loader that provided the value or nil if the value was not provided by a loader
+	Loader model.Loader
+	// LoaderIndex is the index of the loader in the order they were added or -1
+	LoaderIndex int
+	// Key is the configuration key used to look up the value
+	Key string
+	// RawValue is the value as provided by the source, before variable expansion and conversion
+	RawValue interface{}
+	// Expanded indicates if ${} expansion took place
+	Expanded bool
+	// FromCache indicates the value was provided by a cache because the loader's source failed
+	FromCache bool
+	// CachedAt is the time the value was stored in the cache if FromCache is true
+	CachedAt time.Time
+}
+
+type valueOrigin struct {
+	loader      model.Loader
+	loaderIndex int
+	rawValue    interface{}
+	expanded    bool
+	flattened   bool
+	nested      bool
+	cachedAt    time.Time
+}
+
+type fillState struct {
+	report        *Report
+	origins       map[string]*valueOrigin
+	knownKeys     map[string]struct{}
+	knownPrefixes []string
+}
+
+// -----------------------------------------------------------------------------
+
+func newFillState(origins map[string]*valueOrigin) *fillState {
+	return &fillState{
+		report: &Report{
+			Fields: make(map[string]ReportField),
+		},
+		origins:   origins,
+		knownKeys: make(map[string]struct{}),
+	}
+}
+
+func (s *fillState) recordKnownKey(key string) {
+	s.knownKeys[key] = struct{}{}
+}
+
+func (s *fillState) recordKnownPrefix(prefix string) {
+	s.knownPrefixes = append(s.knownPrefixes, prefix)
+}
+
+func (s *fillState) recordValue(fieldPath string, key string) {
+	if origin, ok := s.findOrigin(key); ok {
+		s.report.Fields[fieldPath] = origin.toReportField(key)
+	}
+}
+
+func (s *fillState) recordPrefixedValues(fieldPath string, prefix string, values map[string]interface{}) {
+	// Use the origin of the first key, in alphabetical order, as the representative of the whole set
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, prefix+k)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if origin, ok := s.origins[key]; ok {
+			field := origin.toReportField(prefix + "*")
+			field.RawValue = values
+			s.report.Fields[fieldPath] = field
+			return
+		}
+	}
+}
+
+func (s *fillState) recordDefault(fieldPath string, key string, defaultValue string) {
+	s.report.Fields[fieldPath] = ReportField{
+		Source:      SourceDefault,
+		LoaderIndex: -1,
+		Key:         key,
+		RawValue:    defaultValue,
+	}
+}
+
+func (s *fillState) findOrigin(key string) (*valueOrigin, bool) {
+	origin, ok := s.origins[key]
+	if ok {
+		return origin, true
+	}
+
+	// If the key references a nested value, use the origin of the top-level object that contains it
+	for idx := len(key) - 1; idx > 0; idx-- {
+		if key[idx] == '.' {
+			origin, ok = s.origins[key[:idx]]
+			if ok {
+				return origin, true
+			}
+		}
+	}
+	return nil, false
+}
+
+func (o *valueOrigin) withValue(v interface{}, nested bool) *valueOrigin {
+	origin := *o
+	origin.rawValue = v
+	origin.nested = nested
+	return &origin
+}
+
+func (o *valueOrigin) toReportField(key string) ReportField {
+	field := ReportField{
+		Source:      SourceEnvVar,
+		Loader:      o.loader,
+		LoaderIndex: o.loaderIndex,
+		Key:         key,
+		RawValue:    o.rawValue,
+		Expanded:    o.expanded,
+		FromCache:   !o.cachedAt.IsZero(),
+		CachedAt:    o.cachedAt,
+	}
+	if o.loader != nil {
+		field.Source = loaderName(o.loader)
+	}
+	return field
+}
+
+// effectiveLoader returns the loader that provided the values if the given one delegates the work to others
+func effectiveLoader(l model.Loader) model.Loader {
+	for {
+		w, ok := l.(model.Wrapper)
+		if !ok {
+			return l
+		}
+		inner := w.Unwrap()
+		if inner == nil {
+			return l
+		}
+		l = inner
+	}
+}
+
+// loaderCachedAt returns the time the values were cached if the loader, or one wrapped by it, provided them from a
+// cache
+func loaderCachedAt(l model.Loader) time.Time {
+	for l != nil {
+		if c, ok := l.(model.Cache); ok {
+			if cachedAt, fromCache := c.CachedAt(); fromCache {
+				return cachedAt
+			}
+		}
+		w, ok := l.(model.Wrapper)
+		if !ok {
+			break
+		}
+		l = w.Unwrap()
+	}
+	return time.Time{}
+}
+
+func loaderName(l model.Loader) string {
+	t := reflect.TypeOf(l)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Name()
+}
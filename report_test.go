@@ -0,0 +1,75 @@
+package configreader_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mxmauro/configreader"
+	"github.com/mxmauro/configreader/internal/testhelpers"
+	"github.com/mxmauro/configreader/loader"
+	"github.com/mxmauro/configreader/model"
+)
+
+// -----------------------------------------------------------------------------
+
+type ReportTest struct {
+	Host     string `config:"REPORT_TEST_HOST"`
+	Port     int    `config:"REPORT_TEST_PORT"`
+	Url      string `config:"REPORT_TEST_URL"`
+	Timeout  int    `config:"REPORT_TEST_TIMEOUT" default:"30"`
+	Database ReportDatabaseTest
+}
+
+type ReportDatabaseTest struct {
+	Name string `config:"REPORT_TEST_DB_NAME"`
+}
+
+// -----------------------------------------------------------------------------
+
+func TestLoadWithReport(t *testing.T) {
+	defer testhelpers.ScopedEnvVars(map[string]string{
+		"REPORT_TEST_PORT": "9000",
+	})()
+
+	// Load configuration
+	_, report, err := configreader.New[ReportTest]().
+		WithLoader(loader.NewMemory().WithData(model.Values{
+			"REPORT_TEST_HOST":    "127.0.0.1",
+			"REPORT_TEST_PORT":    8000,
+			"REPORT_TEST_DB_NAME": "first",
+		})).
+		WithLoader(loader.NewCallback().WithCallback(func(_ context.Context) (model.Values, error) {
+			return model.Values{
+				"REPORT_TEST_URL":     "http://${REPORT_TEST_HOST}:${REPORT_TEST_PORT}",
+				"REPORT_TEST_DB_NAME": "second",
+			}, nil
+		})).
+		LoadWithReport(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	// Check the report
+	checkReportField(t, report, "Host", "Memory", 0, false)
+	checkReportField(t, report, "Port", configreader.SourceEnvVar, -1, false)
+	checkReportField(t, report, "Url", "Callback", 1, true)
+	checkReportField(t, report, "Timeout", configreader.SourceDefault, -1, false)
+	checkReportField(t, report, "Database.Name", "Callback", 1, false)
+
+	if report.Fields["Url"].RawValue != "http://${REPORT_TEST_HOST}:${REPORT_TEST_PORT}" {
+		t.Fatalf("unexpected raw value for field Url [value=%v]", report.Fields["Url"].RawValue)
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+func checkReportField(t *testing.T, report *configreader.Report, path string, source string, loaderIndex int, expanded bool) {
+	field, ok := report.Fields[path]
+	if !ok {
+		t.Fatalf("field %s not found in report", path)
+	}
+	if field.Source != source || field.LoaderIndex != loaderIndex || field.Expanded != expanded {
+		t.Fatalf("unexpected report for field %s [source=%v, loaderIndex=%v, expanded=%v]", path, field.Source,
+			field.LoaderIndex, field.Expanded)
+	}
+}
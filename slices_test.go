@@ -0,0 +1,112 @@
+package configreader_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mxmauro/configreader"
+	"github.com/mxmauro/configreader/loader"
+	"github.com/mxmauro/configreader/model"
+)
+
+// -----------------------------------------------------------------------------
+
+type SlicesTest struct {
+	Names     []string             `config:"TEST_NAMES"`
+	Ports     []int                `config:"TEST_PORTS" sep:";"`
+	Timeouts  []time.Duration      `config:"TEST_TIMEOUTS"`
+	Ratios    [2]float64           `config:"TEST_RATIOS"`
+	Flags     []*bool              `config:"TEST_FLAGS"`
+	Endpoints []SlicesEndpointTest `config:"TEST_ENDPOINTS"`
+	Empty     []string             `config:"TEST_EMPTY" default:""`
+}
+
+type SlicesEndpointTest struct {
+	Host string `config:"host"`
+	Port uint16 `config:"port"`
+}
+
+type SlicesOverflowTest struct {
+	Values []int8 `config:"TEST_VALUES"`
+}
+
+// -----------------------------------------------------------------------------
+
+func TestSlices(t *testing.T) {
+	// Load configuration
+	settings, err := configreader.New[SlicesTest]().
+		WithLoader(loader.NewMemory().WithData(model.Values{
+			"TEST_NAMES":    "alpha, beta,gamma",
+			"TEST_PORTS":    "80;443",
+			"TEST_TIMEOUTS": []interface{}{"1s", "2m", "1d"},
+			"TEST_RATIOS":   []interface{}{0.5, 1},
+			"TEST_FLAGS":    "true,0",
+			"TEST_ENDPOINTS": []interface{}{
+				map[string]interface{}{"host": "10.0.0.1", "port": 8080},
+				map[string]interface{}{"host": "10.0.0.2", "port": 8081},
+			},
+		})).
+		Load(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	// Check if settings are the expected
+	trueValue := true
+	falseValue := false
+	expected := SlicesTest{
+		Names:    []string{"alpha", "beta", "gamma"},
+		Ports:    []int{80, 443},
+		Timeouts: []time.Duration{time.Second, 2 * time.Minute, 24 * time.Hour},
+		Ratios:   [2]float64{0.5, 1},
+		Flags:    []*bool{&trueValue, &falseValue},
+		Endpoints: []SlicesEndpointTest{
+			{Host: "10.0.0.1", Port: 8080},
+			{Host: "10.0.0.2", Port: 8081},
+		},
+		Empty: []string{},
+	}
+	if !reflect.DeepEqual(settings, &expected) {
+		t.Fatalf("settings mismatch")
+	}
+}
+
+func TestSlicesElementOverflow(t *testing.T) {
+	// Load configuration
+	_, err := configreader.New[SlicesOverflowTest]().
+		WithLoader(loader.NewMemory().WithData(model.Values{
+			"TEST_VALUES": "1,2,300",
+		})).
+		Load(context.Background())
+	if err == nil {
+		t.Fatalf("unexpected success")
+	}
+	if !strings.Contains(err.Error(), "Values[2]") {
+		t.Fatalf("unexpected error [err=%v]", err)
+	}
+}
+
+func TestSlicesStructElementFromScalar(t *testing.T) {
+	// Struct elements must not be populated from the top-level keys
+	_, err := configreader.New[SlicesTest]().
+		WithLoader(loader.NewMemory().WithData(model.Values{
+			"TEST_NAMES":     "alpha",
+			"TEST_PORTS":     "80",
+			"TEST_TIMEOUTS":  "1s",
+			"TEST_RATIOS":    "0.5,1",
+			"TEST_FLAGS":     "true",
+			"TEST_ENDPOINTS": "a,b",
+			"host":           "global",
+			"port":           80,
+		})).
+		Load(context.Background())
+	if err == nil {
+		t.Fatalf("unexpected success")
+	}
+	if !strings.Contains(err.Error(), "Endpoints[0]") {
+		t.Fatalf("unexpected error [err=%v]", err)
+	}
+}
@@ -0,0 +1,126 @@
+package configreader
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/mxmauro/configreader/internal/helpers"
+)
+
+// -----------------------------------------------------------------------------
+
+func (s *fillState) checkUnknownKeys(flattenSeparator string) error {
+	unknownKeys := make([]UnknownKey, 0)
+
+	for key, origin := range s.origins {
+		// Skip environment variables, keys generated while flattening nested objects and values inside objects
+		if origin.loader == nil || origin.flattened || origin.nested {
+			continue
+		}
+
+		s.collectUnknownKeys([]string{key}, origin, flattenSeparator, &unknownKeys)
+	}
+
+	if len(unknownKeys) == 0 {
+		return nil
+	}
+
+	// Sort keys to produce a deterministic error message
+	sort.Slice(unknownKeys, func(i, j int) bool {
+		return unknownKeys[i].Key < unknownKeys[j].Key
+	})
+
+	// Done
+	return &UnknownKeysError{
+		Keys: unknownKeys,
+	}
+}
+
+func (s *fillState) collectUnknownKeys(path []string, origin *valueOrigin, flattenSeparator string, unknownKeys *[]UnknownKey) {
+	candidates := keyCandidates(path, flattenSeparator)
+	if s.isKnown(candidates) {
+		return
+	}
+
+	// Check each nested value, if any
+	var value interface{} = origin.rawValue
+	for _, part := range path[1:] {
+		m, _ := helpers.ToStringMap(value)
+		value = m[part]
+	}
+	if m, ok := helpers.ToStringMap(value); ok && len(m) > 0 {
+		for k := range m {
+			childPath := make([]string, len(path)+1)
+			copy(childPath, path)
+			childPath[len(path)] = k
+			s.collectUnknownKeys(childPath, origin, flattenSeparator, unknownKeys)
+		}
+		return
+	}
+
+	// Look for similar keys using the full path only
+	key := strings.Join(path, ".")
+	names := []string{key}
+	if len(path) > 1 && len(flattenSeparator) > 0 {
+		names = append(names, strings.ToUpper(strings.Join(path, flattenSeparator)))
+	}
+
+	*unknownKeys = append(*unknownKeys, UnknownKey{
+		Key:        key,
+		Source:     loaderName(origin.loader),
+		Suggestion: s.suggestKey(names),
+	})
+}
+
+// isKnown returns true if a field uses the key, one of its parent objects or a prefix of it
+func (s *fillState) isKnown(candidates []string) bool {
+	for _, candidate := range candidates {
+		if _, ok := s.knownKeys[candidate]; ok {
+			return true
+		}
+		for _, prefix := range s.knownPrefixes {
+			if len(candidate) > len(prefix) && strings.HasPrefix(candidate, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (s *fillState) suggestKey(names []string) string {
+	suggestion := ""
+	bestDistance := 0
+
+	for knownKey := range s.knownKeys {
+		for _, name := range names {
+			// Allow up to a third of the characters to be different
+			maxDistance := len(name) / 3
+			if maxDistance < 1 {
+				maxDistance = 1
+			}
+
+			distance := helpers.EditDistance(strings.ToUpper(name), strings.ToUpper(knownKey))
+			if distance <= maxDistance &&
+				(len(suggestion) == 0 || distance < bestDistance || (distance == bestDistance && knownKey < suggestion)) {
+				suggestion = knownKey
+				bestDistance = distance
+			}
+		}
+	}
+
+	// Done
+	return suggestion
+}
+
+// keyCandidates returns the names a field can use to reference the value at the given path or any of its parents,
+// i.e. "db", "db.host" and "DB_HOST" for the "host" value inside the "db" object
+func keyCandidates(path []string, flattenSeparator string) []string {
+	candidates := make([]string, 0, 2*len(path))
+	for idx := 1; idx <= len(path); idx++ {
+		candidates = append(candidates, strings.Join(path[:idx], "."))
+		if idx > 1 && len(flattenSeparator) > 0 {
+			candidates = append(candidates, strings.ToUpper(strings.Join(path[:idx], flattenSeparator)))
+		}
+	}
+	return candidates
+}
@@ -0,0 +1,89 @@
+package configreader_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mxmauro/configreader"
+	"github.com/mxmauro/configreader/internal/testhelpers"
+	"github.com/mxmauro/configreader/loader"
+	"github.com/mxmauro/configreader/model"
+)
+
+// -----------------------------------------------------------------------------
+
+type StrictTest struct {
+	DatabaseUrl string          `config:"STRICT_TEST_DATABASE_URL" default:""`
+	Features    map[string]bool `config:"STRICT_TEST_FEATURE_*"`
+	Cache       StrictCacheTest `config:"cache"`
+	Port        int             `config:"STRICT_TEST_SERVER_PORT" default:"80"`
+}
+
+type StrictCacheTest struct {
+	Size int `config:"size" default:"0"`
+}
+
+// -----------------------------------------------------------------------------
+
+func TestStrict(t *testing.T) {
+	defer testhelpers.ScopedEnvVars(map[string]string{
+		"STRICT_TEST_UNUSED_ENV_VAR": "1",
+	})()
+
+	// A valid configuration must load
+	_, err := configreader.New[StrictTest]().
+		WithLoader(loader.NewMemory().WithData(model.Values{
+			"STRICT_TEST_DATABASE_URL":   "postgres://localhost",
+			"STRICT_TEST_FEATURE_SEARCH": true,
+			"cache":                      map[string]interface{}{"size": 10},
+			"strict_test": map[string]interface{}{
+				"server": map[string]interface{}{
+					"port": 8080,
+				},
+			},
+		})).
+		WithFlattenedKeys("_").
+		WithStrict().
+		Load(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	// Now add some typos
+	_, err = configreader.New[StrictTest]().
+		WithLoader(loader.NewMemory().WithData(model.Values{
+			"STRICT_TEST_DATABSE_URL":    "postgres://localhost",
+			"STRICT_TEST_FEATURE_SEARCH": true,
+			"strict_test": map[string]interface{}{
+				"server": map[string]interface{}{
+					"prot": 8080,
+				},
+			},
+		})).
+		WithFlattenedKeys("_").
+		WithStrict().
+		Load(context.Background())
+	if err == nil {
+		t.Fatalf("unknown keys were not detected")
+	}
+
+	var unknownKeysErr *configreader.UnknownKeysError
+	if !errors.As(err, &unknownKeysErr) {
+		t.Fatalf("unexpected error [err=%v]", err)
+	}
+	if len(unknownKeysErr.Keys) != 2 {
+		t.Fatalf("unexpected unknown keys count [err=%v]", err)
+	}
+	checkUnknownKey(t, unknownKeysErr.Keys[0], "STRICT_TEST_DATABSE_URL", "STRICT_TEST_DATABASE_URL")
+	checkUnknownKey(t, unknownKeysErr.Keys[1], "strict_test.server.prot", "STRICT_TEST_SERVER_PORT")
+}
+
+// -----------------------------------------------------------------------------
+
+func checkUnknownKey(t *testing.T, unknownKey configreader.UnknownKey, key string, suggestion string) {
+	if unknownKey.Key != key || unknownKey.Source != "Memory" || unknownKey.Suggestion != suggestion {
+		t.Fatalf("unexpected unknown key [key=%v, source=%v, suggestion=%v]", unknownKey.Key, unknownKey.Source,
+			unknownKey.Suggestion)
+	}
+}
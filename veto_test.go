@@ -0,0 +1,93 @@
+package configreader_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mxmauro/configreader"
+	"github.com/mxmauro/configreader/loader"
+	"github.com/mxmauro/configreader/model"
+)
+
+// -----------------------------------------------------------------------------
+
+type VetoTest struct {
+	Value int `config:"VETO_TEST_VALUE"`
+}
+
+// -----------------------------------------------------------------------------
+
+func TestMonitorReloadGuard(t *testing.T) {
+	var value int32 = 1
+	var rejections int32
+
+	errCh := make(chan error, 10)
+	settingsCh := make(chan *VetoTest, 10)
+	holder := configreader.NewHolder[VetoTest]()
+
+	settingsMonitor := configreader.NewMonitor[VetoTest](100*time.Millisecond, func(settings *VetoTest, loadErr error) {
+		if loadErr != nil {
+			errCh <- loadErr
+		} else {
+			settingsCh <- settings
+		}
+	}).WithReloadGuard(func(_ context.Context, previous *VetoTest, settings *VetoTest) error {
+		if previous.Value != 1 {
+			t.Errorf("unexpected previous value %d (should be 1)", previous.Value)
+		}
+		// Reject the new settings twice
+		if atomic.AddInt32(&rejections, 1) <= 2 {
+			return errors.New("cannot apply new settings")
+		}
+		return nil
+	})
+	defer settingsMonitor.Destroy()
+
+	_, err := configreader.New[VetoTest]().
+		WithLoader(loader.NewCallback().WithCallback(func(_ context.Context) (model.Values, error) {
+			return model.Values{
+				"VETO_TEST_VALUE": atomic.LoadInt32(&value),
+			}, nil
+		})).
+		WithHolder(holder).
+		WithMonitor(settingsMonitor).
+		Load(context.Background())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	atomic.StoreInt32(&value, 2)
+
+	// Check the new settings are rejected and retried
+	for i := 0; i < 2; i++ {
+		select {
+		case err = <-errCh:
+			var rejectedErr *configreader.ReloadRejectedError
+			if !errors.As(err, &rejectedErr) {
+				t.Fatalf("unexpected error [err=%v]", err)
+			}
+			if holder.Get().Value != 1 {
+				t.Fatalf("rejected settings were applied")
+			}
+		case <-settingsCh:
+			t.Fatalf("rejected settings were notified")
+		case <-time.After(5 * time.Second):
+			t.Fatalf("rejection not received")
+		}
+	}
+
+	// And finally accepted
+	select {
+	case settings := <-settingsCh:
+		if settings.Value != 2 || holder.Get().Value != 2 {
+			t.Fatalf("unexpected value %d (should be 2)", settings.Value)
+		}
+	case err = <-errCh:
+		t.Fatalf("unexpected error [err=%v]", err)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("settings change not received")
+	}
+}